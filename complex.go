@@ -0,0 +1,238 @@
+package equations
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"math/cmplx"
+	"sort"
+)
+
+var complexFunctions = map[string]func(complex128) complex128{
+	"sin": cmplx.Sin,
+	"cos": cmplx.Cos,
+	"exp": cmplx.Exp,
+	"ln":  cmplx.Log,
+	"abs": func(c complex128) complex128 { return complex(cmplx.Abs(c), 0) },
+}
+
+func Complex(real, imaginary float64) value {
+	return value{number: real, imaginary: imaginary, op: "num"}
+}
+
+func I() value {
+	return Complex(0, 1)
+}
+
+func (v value) Imaginary() float64 {
+	switch v.op {
+	default:
+		panic("value " + v.String() + " is not terminal")
+	case "num":
+		return v.imaginary
+	}
+}
+
+func isComplex(v value) bool {
+	return v.op == "num" && v.imaginary != 0
+}
+
+func complexOf(v value) complex128 {
+	return complex(v.number, v.imaginary)
+}
+
+func fromComplex(c complex128) value {
+	return Complex(real(c), imag(c))
+}
+
+func formatComplex(v value) string {
+	switch {
+	case v.number == 0:
+		return fmt.Sprintf("%fi", v.imaginary)
+	case v.imaginary < 0:
+		return fmt.Sprintf("%f - %fi", v.number, -v.imaginary)
+	default:
+		return fmt.Sprintf("%f + %fi", v.number, v.imaginary)
+	}
+}
+
+func operand(v *value) string {
+	if isComplex(*v) && v.number != 0 {
+		return "(" + v.String() + ")"
+	}
+	return v.String()
+}
+
+func complexPow(base, exponent complex128) complex128 {
+	if imag(exponent) == 0 && real(exponent) == math.Trunc(real(exponent)) && math.Abs(real(exponent)) <= 64 {
+		result := complex(1, 0)
+		for i := 0; i < int(math.Abs(real(exponent))); i++ {
+			result *= base
+		}
+		if real(exponent) < 0 {
+			return 1 / result
+		}
+		return result
+	}
+	return cmplx.Pow(base, exponent)
+}
+
+func EvaluateComplex(val value, vars map[string]complex128) (complex128, error) {
+	if f, unary := complexFunctions[val.op]; unary {
+		arg, err := EvaluateComplex(*val.left, vars)
+		if err != nil {
+			return 0, err
+		}
+		return f(arg), nil
+	}
+
+	switch val.op {
+	default:
+		return 0, errors.New("cannot evaluate operator " + val.op)
+	case "num":
+		return complexOf(val), nil
+	case "const":
+		return complex(val.number, 0), nil
+	case "var":
+		x, present := vars[val.name]
+		if !present {
+			return 0, errors.New("no value for variable " + val.name)
+		}
+		return complex(val.number, 0) * complexPow(x, complex(val.exponent, 0)), nil
+	case "+", "-", "*", "/", "^":
+		l, err := EvaluateComplex(*val.left, vars)
+		if err != nil {
+			return 0, err
+		}
+		r, err := EvaluateComplex(*val.right, vars)
+		if err != nil {
+			return 0, err
+		}
+		switch val.op {
+		case "+":
+			return l + r, nil
+		case "-":
+			return l - r, nil
+		case "*":
+			return l * r, nil
+		case "/":
+			return l / r, nil
+		default:
+			return complexPow(l, r), nil
+		}
+	}
+}
+
+const complexTolerance = 1e-12
+
+func SolveComplexTo(eq *equation, varName string) (*SolutionSet, error) {
+	p, err := toPolynomial(Sub(eq.left, eq.right))
+	if err != nil {
+		return nil, &SolveError{err, eq}
+	}
+	for _, name := range p.variables() {
+		if name != varName {
+			return nil, &SolveError{errors.New("complex roots need a univariate polynomial in " + varName), eq}
+		}
+	}
+
+	u := p.univariate(varName)
+	switch {
+	case len(u) == 0:
+		return &SolutionSet{Kind: AllReals}, nil
+	case u.isConstant():
+		return &SolutionSet{Kind: EmptySet}, nil
+	}
+
+	roots := make([]complex128, 0, u.degree())
+	for _, f := range factorUnivariate(u) {
+		for _, root := range complexRoots(f.base) {
+			for i := 0; i < f.multiplicity; i++ {
+				roots = append(roots, root)
+			}
+		}
+	}
+	sort.Slice(roots, func(i, j int) bool {
+		if real(roots[i]) != real(roots[j]) {
+			return real(roots[i]) < real(roots[j])
+		}
+		return imag(roots[i]) > imag(roots[j])
+	})
+
+	values := make([]value, 0, len(roots))
+	for _, root := range roots {
+		values = append(values, fromComplex(root))
+	}
+	return &SolutionSet{Kind: FiniteSet, Values: values}, nil
+}
+
+func complexRoots(p upoly) []complex128 {
+	coefficients := make([]complex128, len(p))
+	for i, c := range p.monic() {
+		f, _ := c.Float64()
+		coefficients[i] = complex(f, 0)
+	}
+
+	switch p.degree() {
+	case 1:
+		root, _ := new(big.Rat).Neg(p.monic()[0]).Float64()
+		return []complex128{complex(root, 0)}
+	case 2:
+		b, c := coefficients[1], coefficients[0]
+		discriminant := cmplx.Sqrt(b*b - 4*c)
+		return []complex128{cleanComplex((-b + discriminant) / 2), cleanComplex((-b - discriminant) / 2)}
+	}
+	return durandKerner(coefficients)
+}
+
+func durandKerner(coefficients []complex128) []complex128 {
+	n := len(coefficients) - 1
+	roots := make([]complex128, n)
+	for i := range roots {
+		roots[i] = cmplx.Pow(complex(0.4, 0.9), complex(float64(i), 0))
+	}
+
+	for iteration := 0; iteration < 500; iteration++ {
+		change := 0.0
+		for i := range roots {
+			denominator := complex(1, 0)
+			for j := range roots {
+				if i != j {
+					denominator *= roots[i] - roots[j]
+				}
+			}
+			delta := evaluatePolynomial(coefficients, roots[i]) / denominator
+			roots[i] -= delta
+			change = math.Max(change, cmplx.Abs(delta))
+		}
+		if change < complexTolerance {
+			break
+		}
+	}
+
+	for i := range roots {
+		roots[i] = cleanComplex(roots[i])
+	}
+	return roots
+}
+
+func evaluatePolynomial(coefficients []complex128, x complex128) complex128 {
+	result := complex(0, 0)
+	for i := len(coefficients) - 1; i >= 0; i-- {
+		result = result*x + coefficients[i]
+	}
+	return result
+}
+
+func cleanComplex(c complex128) complex128 {
+	scale := math.Max(1, cmplx.Abs(c))
+	re, im := real(c), imag(c)
+	if math.Abs(re) < complexTolerance*scale {
+		re = 0
+	}
+	if math.Abs(im) < complexTolerance*scale {
+		im = 0
+	}
+	return complex(re, im)
+}
@@ -0,0 +1,140 @@
+package equations_test
+
+import (
+	"math"
+	"math/cmplx"
+	"testing"
+
+	"github.com/gossie/equations"
+)
+
+func TestComplex_String(t *testing.T) {
+	tests := []struct {
+		real, imaginary float64
+		expected        string
+	}{
+		{1, 2, "1.000000 + 2.000000i"},
+		{1, -2, "1.000000 - 2.000000i"},
+		{0, 3, "3.000000i"},
+		{4, 0, "4.000000"},
+	}
+
+	for _, test := range tests {
+		if result := equations.Complex(test.real, test.imaginary).String(); result != test.expected {
+			t.Fatalf("expected %v to be %v", result, test.expected)
+		}
+	}
+}
+
+func TestComplex_StringInsideOperations(t *testing.T) {
+	product := equations.Mul(equations.Complex(1, 2), equations.Var(1, "x", 1))
+	if product.String() != "((1.000000 + 2.000000i) * 1.000000x)" {
+		t.Fatalf("expected %v to be ((1.000000 + 2.000000i) * 1.000000x)", product)
+	}
+
+	power := equations.Pow(equations.Complex(1, -1), equations.I())
+	if power.String() != "((1.000000 - 1.000000i) ^ 1.000000i)" {
+		t.Fatalf("expected %v to be ((1.000000 - 1.000000i) ^ 1.000000i)", power)
+	}
+}
+
+func TestComplex_folding(t *testing.T) {
+	eq1 := equations.NewEquation(equations.Mul(equations.I(), equations.I()), equations.Num(-1))
+	if !eq1.IsTrue() {
+		t.Fatalf("expected %v to be true", eq1)
+	}
+
+	eq2 := equations.NewEquation(equations.Add(equations.Complex(1, 2), equations.Sub(equations.Num(3), equations.Complex(0, 5))), equations.Complex(4, -3))
+	if !eq2.IsTrue() {
+		t.Fatalf("expected %v to be true", eq2)
+	}
+
+	eq3 := equations.NewEquation(equations.Div(equations.Complex(1, 1), equations.Complex(1, -1)), equations.I())
+	if !eq3.IsTrue() {
+		t.Fatalf("expected %v to be true", eq3)
+	}
+
+	eq4 := equations.NewEquation(equations.Pow(equations.Complex(1, 1), equations.Num(2)), equations.Complex(0, 2))
+	if !eq4.IsTrue() {
+		t.Fatalf("expected %v to be true", eq4)
+	}
+}
+
+func TestEvaluateComplex(t *testing.T) {
+	expression := equations.Add(equations.Exp(equations.Mul(equations.Complex(0, math.Pi), equations.Var(1, "x", 1))), equations.Num(1))
+
+	result, err := equations.EvaluateComplex(expression, map[string]complex128{"x": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if cmplx.Abs(result) > 1e-12 {
+		t.Fatalf("expected %v to be 0", result)
+	}
+}
+
+func TestSolveComplexTo(t *testing.T) {
+	eq1 := equations.NewEquation(equations.Add(equations.Var(1, "x", 2), equations.Num(1)), equations.Num(0))
+	eq2 := equations.NewEquation(equations.Add(equations.Add(equations.Var(1, "x", 2), equations.Var(2, "x", 1)), equations.Num(5)), equations.Num(0))
+	eq3 := equations.NewEquation(equations.Var(1, "x", 3), equations.Num(1))
+	eq4 := equations.NewEquation(equations.Var(1, "x", 2), equations.Num(4))
+
+	solutions1, _ := equations.SolveComplexTo(&eq1, "x")
+	solutions2, _ := equations.SolveComplexTo(&eq2, "x")
+	solutions3, _ := equations.SolveComplexTo(&eq3, "x")
+	solutions4, _ := equations.SolveComplexTo(&eq4, "x")
+
+	if solutions1.String() != "{1.000000i, -1.000000i}" {
+		t.Fatalf("expected %v to be {1.000000i, -1.000000i}", solutions1)
+	}
+	if solutions2.String() != "{-1.000000 + 2.000000i, -1.000000 - 2.000000i}" {
+		t.Fatalf("expected %v to be {-1.000000 + 2.000000i, -1.000000 - 2.000000i}", solutions2)
+	}
+	if solutions3.String() != "{-0.500000 + 0.866025i, -0.500000 - 0.866025i, 1.000000}" {
+		t.Fatalf("expected %v to be {-0.500000 + 0.866025i, -0.500000 - 0.866025i, 1.000000}", solutions3)
+	}
+	if solutions4.String() != "{-2.000000, 2.000000}" {
+		t.Fatalf("expected %v to be {-2.000000, 2.000000}", solutions4)
+	}
+}
+
+func TestSolveComplexTo_multiplicities(t *testing.T) {
+	eq1 := equations.NewEquation(equations.Pow(equations.Add(equations.Var(1, "x", 2), equations.Num(1)), equations.Num(2)), equations.Num(0))
+	eq2 := equations.NewEquation(equations.Mul(equations.Pow(equations.Sub(equations.Var(1, "x", 1), equations.Num(1)), equations.Num(3)), equations.Var(1, "x", 1)), equations.Num(0))
+
+	solutions1, _ := equations.SolveComplexTo(&eq1, "x")
+	solutions2, _ := equations.SolveComplexTo(&eq2, "x")
+
+	if solutions1.String() != "{1.000000i, 1.000000i, -1.000000i, -1.000000i}" {
+		t.Fatalf("expected %v to be {1.000000i, 1.000000i, -1.000000i, -1.000000i}", solutions1)
+	}
+	if solutions2.String() != "{0.000000, 1.000000, 1.000000, 1.000000}" {
+		t.Fatalf("expected %v to be {0.000000, 1.000000, 1.000000, 1.000000}", solutions2)
+	}
+}
+
+func TestSolveComplexTo_durandKerner(t *testing.T) {
+	polynomial := equations.Add(equations.Add(equations.Var(1, "x", 5), equations.Var(1, "x", 1)), equations.Num(-1))
+	eq := equations.NewEquation(polynomial, equations.Num(0))
+
+	solutions, err := equations.SolveComplexTo(&eq, "x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(solutions.Values) != 5 {
+		t.Fatalf("expected five roots, got %v", solutions)
+	}
+	for _, root := range solutions.Values {
+		residual, _ := equations.EvaluateComplex(polynomial, map[string]complex128{"x": complex(root.Number(), root.Imaginary())})
+		if cmplx.Abs(residual) > 1e-9 {
+			t.Fatalf("expected %v to be a root, residual %v", root, residual)
+		}
+	}
+}
+
+func TestSolveComplexTo_notUnivariate(t *testing.T) {
+	eq := equations.NewEquation(equations.Mul(equations.Var(1, "x", 2), equations.Var(1, "y", 1)), equations.Num(1))
+
+	if _, err := equations.SolveComplexTo(&eq, "x"); err == nil {
+		t.Fatal("expected an error")
+	}
+}
@@ -0,0 +1,98 @@
+package equations
+
+import (
+	"errors"
+	"math"
+	"math/big"
+	"sync"
+)
+
+type constantRegistry struct {
+	mutex  sync.RWMutex
+	values map[string]float64
+}
+
+var constants = &constantRegistry{values: map[string]float64{
+	"π": math.Pi,
+	"ℯ": math.E,
+}}
+
+func (r *constantRegistry) value(name string) (float64, bool) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	x, present := r.values[name]
+	return x, present
+}
+
+func builtin(name string) bool {
+	return name == "π" || name == "ℯ"
+}
+
+func Pi() value {
+	return constant("π", math.Pi)
+}
+
+func E() value {
+	return constant("ℯ", math.E)
+}
+
+func constant(name string, number float64) value {
+	return value{op: "const", name: name, number: number}
+}
+
+func DeclareConstant(name string, number float64) (value, error) {
+	constants.mutex.Lock()
+	defer constants.mutex.Unlock()
+	if existing, present := constants.values[name]; present && existing != number {
+		return value{}, errors.New("constant " + name + " is already declared")
+	}
+	constants.values[name] = number
+	return constant(name, number), nil
+}
+
+func RemoveConstant(name string) error {
+	if builtin(name) {
+		return errors.New("cannot remove built-in constant " + name)
+	}
+	constants.mutex.Lock()
+	defer constants.mutex.Unlock()
+	delete(constants.values, name)
+	return nil
+}
+
+func Constant(name string) (value, error) {
+	number, present := constants.value(name)
+	if !present {
+		return value{}, errors.New("unknown constant " + name)
+	}
+	return constant(name, number), nil
+}
+
+func (v value) IsConstant() bool {
+	return v.op == "const"
+}
+
+func SubstituteConstants(val value) value {
+	if val.op == "const" {
+		return Num(val.number)
+	}
+	if val.left != nil {
+		l := SubstituteConstants(*val.left)
+		val.left = &l
+	}
+	if val.right != nil {
+		r := SubstituteConstants(*val.right)
+		val.right = &r
+	}
+	return val
+}
+
+func (s *Simplifier) bigConstant(c value) *big.Float {
+	switch c.name {
+	case "π":
+		return s.bigPi()
+	case "ℯ":
+		return s.bigExp(s.newFloat().SetInt64(1))
+	}
+	return s.newFloat().SetFloat64(c.number)
+}
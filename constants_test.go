@@ -0,0 +1,153 @@
+package equations_test
+
+import (
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/gossie/equations"
+)
+
+func TestConstants_staySymbolic(t *testing.T) {
+	eq := equations.NewEquation(equations.Div(equations.Mul(equations.Num(2), equations.Pi()), equations.Pi()), equations.Num(2))
+	if !eq.IsTrue() {
+		t.Fatalf("expected %v to be true", eq)
+	}
+
+	circumference := equations.NewEquation(equations.Var(1, "c", 1), equations.Mul(equations.Mul(equations.Num(2), equations.Pi()), equations.Var(1, "r", 1)))
+	result, _ := equations.SolveTo(&circumference, "r")
+	if !strings.Contains(result.String(), "π") {
+		t.Fatalf("expected %v to mention π", result)
+	}
+}
+
+func TestConstants_String(t *testing.T) {
+	result := equations.NewEquation(equations.Mul(equations.Pi(), equations.E()), equations.Num(0))
+	if result.String() != "(π * ℯ) = 0.000000" {
+		t.Fatalf("expected %v to be (π * ℯ) = 0.000000", result)
+	}
+}
+
+func TestConstants_evaluate(t *testing.T) {
+	result, err := equations.Evaluate(equations.Mul(equations.Mul(equations.Num(2), equations.Pi()), equations.Var(1, "r", 2)), map[string]float64{"r": 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != 2*math.Pi*9 {
+		t.Fatalf("expected %v to be %v", result, 2*math.Pi*9)
+	}
+
+	precise, err := equations.NewBigSimplifier(200, big.ToNearestEven).Evaluate(equations.Pi(), nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(precise.Text('f', 60), "3.1415926535897932384626433832795028841971693993751058209") {
+		t.Fatalf("expected %v to be π", precise.Text('f', 60))
+	}
+}
+
+func TestDeclareConstant(t *testing.T) {
+	g, err := equations.DeclareConstant("g", 9.80665)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { equations.RemoveConstant("g") })
+	if !g.IsConstant() || equations.Var(1, "h", 1).IsConstant() {
+		t.Fatal("expected only g to be a constant")
+	}
+
+	if _, err := equations.DeclareConstant("g", 10); err == nil {
+		t.Fatal("expected redeclaring g to fail")
+	}
+
+	weight := equations.SubstituteConstants(equations.Mul(equations.Num(2), g))
+	if weight.String() != "(2.000000 * 9.806650)" {
+		t.Fatalf("expected %v to be (2.000000 * 9.806650)", weight)
+	}
+}
+
+func TestRemoveConstant(t *testing.T) {
+	if _, err := equations.DeclareConstant("c0", 299792458); err != nil {
+		t.Fatal(err)
+	}
+	if err := equations.RemoveConstant("c0"); err != nil {
+		t.Fatal(err)
+	}
+	if equations.Var(1, "c0", 1).IsConstant() {
+		t.Fatal("expected c0 to be removed")
+	}
+	if err := equations.RemoveConstant("π"); err == nil {
+		t.Fatal("expected π not to be removable")
+	}
+}
+
+func TestConstants_doNotBindVariables(t *testing.T) {
+	if _, err := equations.Evaluate(equations.Var(1, "e", 1), nil); err == nil {
+		t.Fatal("expected the variable e to need a value")
+	}
+	if result, _ := equations.Evaluate(equations.Var(1, "e", 1), map[string]float64{"e": 2}); result != 2 {
+		t.Fatalf("expected %v to be 2", result)
+	}
+	if result, _ := equations.Evaluate(equations.E(), nil); result != math.E {
+		t.Fatalf("expected %v to be %v", result, math.E)
+	}
+}
+
+func TestDeclareConstant_concurrently(t *testing.T) {
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			name := fmt.Sprintf("k%d", i)
+			k, err := equations.DeclareConstant(name, float64(i))
+			if err != nil {
+				t.Error(err)
+			}
+			if _, err := equations.Evaluate(equations.Mul(equations.Pi(), k), nil); err != nil {
+				t.Error(err)
+			}
+			equations.RemoveConstant(name)
+		}(i)
+	}
+	wg.Wait()
+}
+
+func TestDeclareConstant_leavesVariablesAlone(t *testing.T) {
+	g, err := equations.DeclareConstant("g0", 9.80665)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { equations.RemoveConstant("g0") })
+
+	variable := equations.Var(1, "g0", 1)
+	if variable.IsConstant() {
+		t.Fatal("expected the variable g0 to stay a variable")
+	}
+	if _, err := equations.Evaluate(variable, nil); err == nil {
+		t.Fatal("expected the variable g0 to need a value")
+	}
+	if result, _ := equations.Evaluate(g, nil); result != 9.80665 {
+		t.Fatalf("expected %v to be 9.80665", result)
+	}
+}
+
+func TestConstants_areNotVariables(t *testing.T) {
+	x := equations.Var(1, "x", 1)
+
+	derivative, _ := equations.Derive(equations.Mul(equations.Pi(), x), "x")
+	if derivative.String() != "π" {
+		t.Fatalf("expected %v to be π", derivative)
+	}
+	if derivative, _ := equations.Derive(equations.Pi(), "π"); derivative.String() != "0.000000" {
+		t.Fatalf("expected %v to be 0.000000", derivative)
+	}
+
+	eq := equations.NewEquation(equations.Mul(equations.Num(2), equations.Pi()), x)
+	if _, err := equations.SolveTo(&eq, "π"); err == nil {
+		t.Fatal("expected π not to be solvable for")
+	}
+}
@@ -0,0 +1,287 @@
+package equations
+
+import (
+	"errors"
+	"fmt"
+	"go/token"
+	"strings"
+)
+
+type Binding struct {
+	Name  string
+	Value value
+}
+
+type Plan struct {
+	Bindings []Binding
+	Result   value
+}
+
+type eliminator struct {
+	interner *Interner
+	counts   map[*value]int
+	names    map[*value]string
+	used     map[string]bool
+	plan     *Plan
+}
+
+func Eliminate(val value) *Plan {
+	e := &eliminator{
+		interner: NewInterner(),
+		counts:   make(map[*value]int),
+		names:    make(map[*value]string),
+		used:     make(map[string]bool),
+		plan:     &Plan{Bindings: make([]Binding, 0)},
+	}
+	e.collectNames(val)
+	e.count(val)
+	e.plan.Result = e.rewrite(val)
+	e.plan.inlineSingleUses()
+	e.renumber()
+	return e.plan
+}
+
+func (e *eliminator) collectNames(val value) {
+	if val.op == "var" {
+		e.used[val.name] = true
+	}
+	if val.left != nil {
+		e.collectNames(*val.left)
+	}
+	if val.right != nil {
+		e.collectNames(*val.right)
+	}
+}
+
+func (e *eliminator) count(val value) {
+	if val.op == "num" || val.op == "var" || val.op == "const" {
+		return
+	}
+	e.counts[e.interner.Intern(val)]++
+	if val.left != nil {
+		e.count(*val.left)
+	}
+	if val.right != nil {
+		e.count(*val.right)
+	}
+}
+
+func (e *eliminator) rewrite(val value) value {
+	if val.op == "num" || val.op == "var" || val.op == "const" {
+		return val
+	}
+	canonical := e.interner.Intern(val)
+	if name, bound := e.names[canonical]; bound {
+		return Var(1, name, 1)
+	}
+
+	if val.left != nil {
+		l := e.rewrite(*val.left)
+		val.left = &l
+	}
+	if val.right != nil {
+		r := e.rewrite(*val.right)
+		val.right = &r
+	}
+	if e.counts[canonical] < 2 {
+		return val
+	}
+
+	name := fmt.Sprintf("#%d", len(e.names)+1)
+	e.names[canonical] = name
+	e.plan.Bindings = append(e.plan.Bindings, Binding{name, val})
+	return Var(1, name, 1)
+}
+
+func (e *eliminator) renumber() {
+	next := 1
+	for i, b := range e.plan.Bindings {
+		for e.used[fmt.Sprintf("t%d", next)] {
+			next++
+		}
+		name := fmt.Sprintf("t%d", next)
+		next++
+
+		reference := Var(1, name, 1)
+		for j := i; j < len(e.plan.Bindings); j++ {
+			e.plan.Bindings[j].Value = replaceVariable(e.plan.Bindings[j].Value, b.Name, reference)
+		}
+		e.plan.Result = replaceVariable(e.plan.Result, b.Name, reference)
+		e.plan.Bindings[i].Name = name
+	}
+}
+
+func (p *Plan) uses() map[string]int {
+	uses := make(map[string]int)
+	var walk func(val value)
+	walk = func(val value) {
+		if val.op == "var" {
+			uses[val.name]++
+		}
+		if val.left != nil {
+			walk(*val.left)
+		}
+		if val.right != nil {
+			walk(*val.right)
+		}
+	}
+	for _, b := range p.Bindings {
+		walk(b.Value)
+	}
+	walk(p.Result)
+	return uses
+}
+
+func (p *Plan) inlineSingleUses() {
+	for changed := true; changed; {
+		changed = false
+		uses := p.uses()
+		for i, b := range p.Bindings {
+			if uses[b.Name] != 1 {
+				continue
+			}
+			bindings := append(append(make([]Binding, 0, len(p.Bindings)-1), p.Bindings[:i]...), p.Bindings[i+1:]...)
+			for j := range bindings {
+				bindings[j].Value = replaceVariable(bindings[j].Value, b.Name, b.Value)
+			}
+			p.Result = replaceVariable(p.Result, b.Name, b.Value)
+			p.Bindings = bindings
+			changed = true
+			break
+		}
+	}
+}
+
+func replaceVariable(val value, name string, replacement value) value {
+	if val.op == "var" && val.name == name {
+		return replacement
+	}
+	if val.left != nil {
+		l := replaceVariable(*val.left, name, replacement)
+		val.left = &l
+	}
+	if val.right != nil {
+		r := replaceVariable(*val.right, name, replacement)
+		val.right = &r
+	}
+	return val
+}
+
+func (p *Plan) String() string {
+	lines := make([]string, 0, len(p.Bindings)+1)
+	for _, b := range p.Bindings {
+		lines = append(lines, fmt.Sprintf("let %v = %v", b.Name, b.Value))
+	}
+	lines = append(lines, p.Result.String())
+	return strings.Join(lines, "\n")
+}
+
+func (p *Plan) Evaluate(vars map[string]float64) (float64, error) {
+	env := inBaseUnits(vars)
+	for _, b := range p.Bindings {
+		x, err := Evaluate(b.Value, env)
+		if err != nil {
+			return 0, err
+		}
+		env[b.Name] = x
+	}
+	return Evaluate(p.Result, env)
+}
+
+var goFunctions = map[string]string{
+	"sin": "math.Sin",
+	"cos": "math.Cos",
+	"exp": "math.Exp",
+	"ln":  "math.Log",
+	"abs": "math.Abs",
+}
+
+func goExpression(val value, params map[string]bool) (string, error) {
+	if f, unary := goFunctions[val.op]; unary {
+		arg, err := goExpression(*val.left, params)
+		return f + "(" + arg + ")", err
+	}
+
+	switch val.op {
+	default:
+		return "", errors.New("cannot generate code for operator " + val.op)
+	case "num":
+		if val.imaginary != 0 {
+			return "", errors.New("cannot generate code for complex number " + val.String())
+		}
+		return formatNumber(val.number), nil
+	case "const":
+		switch val.name {
+		case "π":
+			return "math.Pi", nil
+		case "ℯ":
+			return "math.E", nil
+		}
+		return formatNumber(val.number), nil
+	case "var":
+		name := val.name
+		switch {
+		case params[name]:
+		case isUnit(name):
+			return formatNumber(val.number), nil
+		default:
+			return "", errors.New("no parameter or binding for variable " + name)
+		}
+		term := name
+		if val.exponent != 1 {
+			term = fmt.Sprintf("math.Pow(%v, %v)", name, formatNumber(val.exponent))
+		}
+		if val.number != 1 {
+			term = formatNumber(val.number) + "*" + term
+		}
+		return term, nil
+	case "+", "-", "*", "/", "^":
+		l, err := goExpression(*val.left, params)
+		if err != nil {
+			return "", err
+		}
+		r, err := goExpression(*val.right, params)
+		if err != nil {
+			return "", err
+		}
+		if val.op == "^" {
+			return "math.Pow(" + l + ", " + r + ")", nil
+		}
+		return "(" + l + " " + val.op + " " + r + ")", nil
+	}
+}
+
+func (p *Plan) Go(name string, params ...string) (string, error) {
+	if !token.IsIdentifier(name) {
+		return "", fmt.Errorf("%q is not a valid Go function name", name)
+	}
+	declared := make(map[string]bool, len(params)+len(p.Bindings))
+	for _, param := range params {
+		if !token.IsIdentifier(param) || param == "math" {
+			return "", fmt.Errorf("%q is not a valid Go parameter name", param)
+		}
+		if declared[param] {
+			return "", fmt.Errorf("parameter %v is declared twice", param)
+		}
+		declared[param] = true
+	}
+	for _, binding := range p.Bindings {
+		declared[binding.Name] = true
+	}
+
+	var b strings.Builder
+	fmt.Fprintf(&b, "func %v(%v float64) float64 {\n", name, strings.Join(params, ", "))
+	for _, binding := range p.Bindings {
+		expression, err := goExpression(binding.Value, declared)
+		if err != nil {
+			return "", err
+		}
+		fmt.Fprintf(&b, "\t%v := %v\n", binding.Name, expression)
+	}
+	result, err := goExpression(p.Result, declared)
+	if err != nil {
+		return "", err
+	}
+	fmt.Fprintf(&b, "\treturn %v\n}\n", result)
+	return b.String(), nil
+}
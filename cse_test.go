@@ -0,0 +1,101 @@
+package equations_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gossie/equations"
+)
+
+func TestEliminate(t *testing.T) {
+	expression := equations.Add(
+		equations.Mul(
+			equations.Div(equations.Add(equations.Var(1, "s", 1), equations.Num(5)), equations.Num(4)),
+			equations.Div(equations.Add(equations.Var(1, "s", 1), equations.Num(5)), equations.Num(4)),
+		),
+		equations.Div(equations.Num(1), equations.Div(equations.Add(equations.Var(1, "s", 1), equations.Num(5)), equations.Num(4))),
+	)
+
+	plan := equations.Eliminate(expression)
+
+	expected := "let t1 = ((1.000000s + 5.000000) / 4.000000)\n((1.000000t1 * 1.000000t1) + (1.000000 / 1.000000t1))"
+	if plan.String() != expected {
+		t.Fatalf("expected\n%v\nto be\n%v", plan, expected)
+	}
+
+	result, err := plan.Evaluate(map[string]float64{"s": 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != 4+0.5 {
+		t.Fatalf("expected %v to be 4.5", result)
+	}
+}
+
+func TestEliminate_nested(t *testing.T) {
+	inner := equations.Sin(equations.Var(1, "x", 1))
+	outer := equations.Add(inner, equations.Num(1))
+	expression := equations.Add(equations.Mul(outer, outer), equations.Div(inner, outer))
+
+	plan := equations.Eliminate(expression)
+	if len(plan.Bindings) != 2 {
+		t.Fatalf("expected two bindings in\n%v", plan)
+	}
+
+	expected, _ := equations.Evaluate(expression, map[string]float64{"x": 0.7})
+	result, _ := plan.Evaluate(map[string]float64{"x": 0.7})
+	if math.Abs(result-expected) > 1e-15 {
+		t.Fatalf("expected %v to be %v", result, expected)
+	}
+}
+
+func TestEliminate_avoidsExistingNames(t *testing.T) {
+	shared := equations.Add(equations.Var(1, "t1", 1), equations.Num(1))
+	plan := equations.Eliminate(equations.Mul(shared, shared))
+
+	if plan.Bindings[0].Name != "t2" {
+		t.Fatalf("expected the binding not to shadow t1 in\n%v", plan)
+	}
+}
+
+func TestPlan_Go(t *testing.T) {
+	shared := equations.Add(equations.Var(2, "x", 2), equations.Pi())
+	plan := equations.Eliminate(equations.Div(equations.Exp(shared), shared))
+
+	code, err := plan.Go("f", "x")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "func f(x float64) float64 {\n\tt1 := (2*math.Pow(x, 2) + math.Pi)\n\treturn (math.Exp(t1) / t1)\n}\n"
+	if code != expected {
+		t.Fatalf("expected\n%v\nto be\n%v", code, expected)
+	}
+}
+
+func TestPlan_Go_invalidNames(t *testing.T) {
+	plan := equations.Eliminate(equations.Add(equations.Var(1, "x", 1), equations.Var(1, "y", 1)))
+
+	if _, err := plan.Go("f", "x"); err == nil {
+		t.Fatal("expected the unbound y to fail")
+	}
+	if _, err := plan.Go("f", "x", "y", "x"); err == nil {
+		t.Fatal("expected the duplicate x to fail")
+	}
+	if _, err := plan.Go("f(", "x", "y"); err == nil {
+		t.Fatal("expected f( not to be a function name")
+	}
+
+	odd := equations.Eliminate(equations.Var(1, "a b", 1))
+	if _, err := odd.Go("f", "a b"); err == nil {
+		t.Fatal("expected a b not to be a parameter name")
+	}
+
+	code, err := equations.Eliminate(equations.Mul(equations.Pi(), equations.Var(1, "r", 1))).Go("area", "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code != "func area(r float64) float64 {\n\treturn (math.Pi * r)\n}\n" {
+		t.Fatalf("expected %v to inline π", code)
+	}
+}
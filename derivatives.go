@@ -0,0 +1,107 @@
+package equations
+
+import "errors"
+
+func Derive(val value, varName string) (value, error) {
+	derivative, err := derive(val, varName)
+	if err != nil {
+		return value{}, err
+	}
+	return derivative.execute(), nil
+}
+
+func derive(val value, varName string) (value, error) {
+	if _, unary := functions[val.op]; unary {
+		inner, err := derive(*val.left, varName)
+		if err != nil {
+			return value{}, err
+		}
+		return deriveFunction(val, inner)
+	}
+
+	switch val.op {
+	default:
+		return value{}, errors.New("cannot derive operator " + val.op)
+	case "num", "const":
+		return Num(0), nil
+	case "matrix", "row":
+		entry, err := derive(*val.left, varName)
+		if err != nil {
+			return value{}, err
+		}
+		val.left = &entry
+		if val.right != nil {
+			rest, err := derive(*val.right, varName)
+			if err != nil {
+				return value{}, err
+			}
+			val.right = &rest
+		}
+		return val, nil
+	case "var":
+		if val.name != varName {
+			return Num(0), nil
+		}
+		if val.exponent == 1 {
+			return Num(val.number), nil
+		}
+		return Var(val.number*val.exponent, val.name, val.exponent-1), nil
+	case "^":
+		return derivePow(val, varName)
+	case "+", "-", "*", "/":
+		l, err := derive(*val.left, varName)
+		if err != nil {
+			return value{}, err
+		}
+		r, err := derive(*val.right, varName)
+		if err != nil {
+			return value{}, err
+		}
+		switch val.op {
+		case "+":
+			return Add(l, r), nil
+		case "-":
+			return Sub(l, r), nil
+		case "*":
+			return Add(Mul(l, *val.right), Mul(*val.left, r)), nil
+		}
+		numerator := Sub(Mul(l, *val.right), Mul(*val.left, r))
+		return Div(numerator, Pow(*val.right, Num(2))), nil
+	}
+}
+
+func deriveFunction(val, inner value) (value, error) {
+	switch val.op {
+	default:
+		return value{}, errors.New("cannot derive function " + val.op)
+	case "sin":
+		return Mul(Cos(*val.left), inner), nil
+	case "cos":
+		return Mul(Num(-1), Mul(Sin(*val.left), inner)), nil
+	case "exp":
+		return Mul(val, inner), nil
+	case "ln":
+		return Div(inner, *val.left), nil
+	case "abs":
+		return Mul(Div(*val.left, val), inner), nil
+	}
+}
+
+func derivePow(val value, varName string) (value, error) {
+	base, exponent := *val.left, *val.right
+	db, err := derive(base, varName)
+	if err != nil {
+		return value{}, err
+	}
+	if exponent.op == "num" {
+		return Mul(Mul(exponent, Pow(base, Num(exponent.number-1))), db), nil
+	}
+	de, err := derive(exponent, varName)
+	if err != nil {
+		return value{}, err
+	}
+	if base.op == "num" {
+		return Mul(Mul(val, Ln(base)), de), nil
+	}
+	return Mul(val, Add(Mul(de, Ln(base)), Div(Mul(exponent, db), base))), nil
+}
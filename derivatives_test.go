@@ -0,0 +1,35 @@
+package equations_test
+
+import (
+	"testing"
+
+	"github.com/gossie/equations"
+)
+
+func TestDerive_polynomial(t *testing.T) {
+	polynomial := equations.Add(equations.Var(3, "x", 2), equations.Add(equations.Var(2, "x", 1), equations.Var(5, "y", 1)))
+
+	derivative, _ := equations.Derive(polynomial, "x")
+	if derivative.String() != "(6.000000x + 2.000000)" {
+		t.Fatalf("expected %v to be (6.000000x + 2.000000)", derivative)
+	}
+}
+
+func TestDerive_chainRule(t *testing.T) {
+	sine := equations.Sin(equations.Var(2, "x", 1))
+
+	derivative, _ := equations.Derive(sine, "x")
+	if derivative.String() != "(cos(2.000000x) * 2.000000)" {
+		t.Fatalf("expected %v to be (cos(2.000000x) * 2.000000)", derivative)
+	}
+}
+
+func TestDerive_quotient(t *testing.T) {
+	quotient := equations.Div(equations.Num(1), equations.Var(1, "x", 1))
+
+	derivative, _ := equations.Derive(quotient, "x")
+	result, _ := equations.Evaluate(derivative, map[string]float64{"x": 2})
+	if result != -0.25 {
+		t.Fatalf("expected %v to be -0.25", result)
+	}
+}
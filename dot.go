@@ -0,0 +1,172 @@
+package equations
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+type TraceStep struct {
+	Rule            string
+	Tree            value
+	Matched, Result value
+	path            []int
+}
+
+func (ts TraceStep) String() string {
+	return fmt.Sprintf("%v: %v -> %v", ts.Rule, ts.Matched, ts.Result)
+}
+
+type tracer struct {
+	simplifier *Simplifier
+	root       value
+	steps      []TraceStep
+}
+
+func (s *Simplifier) Trace(v value) (value, []TraceStep) {
+	t := &tracer{simplifier: s, root: v, steps: make([]TraceStep, 0)}
+	return t.simplify(v, nil), t.steps
+}
+
+func (t *tracer) simplify(v value, path []int) value {
+	if v.left != nil {
+		l := t.simplify(*v.left, appendPath(path, 0))
+		v.left = &l
+	}
+	if v.right != nil {
+		r := t.simplify(*v.right, appendPath(path, 1))
+		v.right = &r
+	}
+	t.root = replaceAt(t.root, path, v)
+
+	for _, pm := range t.simplifier.patternMatchers() {
+		if v.held && expands(pm) {
+			continue
+		}
+		if pm.Match(&v) {
+			result := pm.Execute()
+			t.steps = append(t.steps, TraceStep{reflect.TypeOf(pm).Elem().Name(), t.root, v, result, path})
+			t.root = replaceAt(t.root, path, result)
+			return t.simplify(result, path)
+		}
+	}
+	return v
+}
+
+func appendPath(path []int, direction int) []int {
+	result := make([]int, len(path), len(path)+1)
+	copy(result, path)
+	return append(result, direction)
+}
+
+func replaceAt(root value, path []int, v value) value {
+	if len(path) == 0 {
+		return v
+	}
+	if path[0] == 0 {
+		l := replaceAt(*root.left, path[1:], v)
+		root.left = &l
+	} else {
+		r := replaceAt(*root.right, path[1:], v)
+		root.right = &r
+	}
+	return root
+}
+
+type dotWriter struct {
+	builder strings.Builder
+	prefix  string
+	next    int
+}
+
+func (w *dotWriter) node(label string, highlighted bool) string {
+	id := fmt.Sprintf("%vn%d", w.prefix, w.next)
+	w.next++
+	style := ""
+	if highlighted {
+		style = ", style=filled, fillcolor=lightcoral"
+	}
+	fmt.Fprintf(&w.builder, "  %v [label=%q%v];\n", id, label, style)
+	return id
+}
+
+func (w *dotWriter) edge(from, to, label string) {
+	fmt.Fprintf(&w.builder, "  %v -> %v [label=%q];\n", from, to, label)
+}
+
+func dotLabel(v value) string {
+	switch v.op {
+	case "num":
+		if v.imaginary != 0 {
+			return "num\n" + formatComplex(v)
+		}
+		return fmt.Sprintf("num\n%v", v)
+	case "var":
+		return fmt.Sprintf("var %v\nfactor %f\nexponent %v", v.name, v.number, v.exponent)
+	case "const":
+		return fmt.Sprintf("const %v\n%v", v.name, v.number)
+	}
+	if v.held {
+		return v.op + "\n(held)"
+	}
+	return v.op
+}
+
+func (w *dotWriter) write(v value, highlight []int, highlighted bool) string {
+	highlighted = highlighted || highlight != nil && len(highlight) == 0
+	id := w.node(dotLabel(v), highlighted)
+
+	children := []*value{v.left, v.right}
+	names := []string{"left", "right"}
+	switch _, unary := functions[v.op]; {
+	case unary:
+		names[0] = "arg"
+	case v.op == "matrix":
+		names = []string{"row", "next"}
+	case v.op == "row":
+		names = []string{"entry", "next"}
+	}
+	for i, child := range children {
+		if child == nil {
+			continue
+		}
+		var next []int
+		if len(highlight) > 0 && highlight[0] == i {
+			next = highlight[1:]
+		}
+		w.edge(id, w.write(*child, next, highlighted), names[i])
+	}
+	return id
+}
+
+func Dot(val value) string {
+	w := &dotWriter{}
+	w.builder.WriteString("digraph expression {\n")
+	w.write(val, nil, false)
+	w.builder.WriteString("}\n")
+	return w.builder.String()
+}
+
+func DotEquation(eq equation) string {
+	w := &dotWriter{}
+	w.builder.WriteString("digraph equation {\n")
+	root := w.node("=", false)
+	w.edge(root, w.write(eq.left, nil, false), "left")
+	w.edge(root, w.write(eq.right, nil, false), "right")
+	w.builder.WriteString("}\n")
+	return w.builder.String()
+}
+
+func DotTrace(steps []TraceStep) string {
+	w := &dotWriter{}
+	w.builder.WriteString("digraph trace {\n")
+	for i, step := range steps {
+		w.prefix = fmt.Sprintf("s%d", i)
+		w.next = 0
+		fmt.Fprintf(&w.builder, "  subgraph cluster_%d {\n  label=%q;\n", i, fmt.Sprintf("%d: %v", i+1, step.Rule))
+		w.write(step.Tree, append(make([]int, 0), step.path...), false)
+		w.builder.WriteString("  }\n")
+	}
+	w.builder.WriteString("}\n")
+	return w.builder.String()
+}
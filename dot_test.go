@@ -0,0 +1,92 @@
+package equations_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gossie/equations"
+)
+
+func TestDot(t *testing.T) {
+	result := equations.Dot(equations.Add(equations.Var(2, "x", 3), equations.Num(1)))
+
+	expected := `digraph expression {
+  n0 [label="+"];
+  n1 [label="var x\nfactor 2.000000\nexponent 3"];
+  n0 -> n1 [label="left"];
+  n2 [label="num\n1.000000"];
+  n0 -> n2 [label="right"];
+}
+`
+	if result != expected {
+		t.Fatalf("expected %v to be %v", result, expected)
+	}
+}
+
+func TestDotEquation(t *testing.T) {
+	result := equations.DotEquation(equations.NewEquation(equations.Sin(equations.Var(1, "x", 1)), equations.Num(0)))
+
+	expected := `digraph equation {
+  n0 [label="="];
+  n1 [label="sin"];
+  n2 [label="var x\nfactor 1.000000\nexponent 1"];
+  n1 -> n2 [label="arg"];
+  n0 -> n1 [label="left"];
+  n3 [label="num\n0.000000"];
+  n0 -> n3 [label="right"];
+}
+`
+	if result != expected {
+		t.Fatalf("expected %v to be %v", result, expected)
+	}
+}
+
+func TestTrace(t *testing.T) {
+	result, steps := equations.NewSimplifier().Trace(equations.Add(equations.Num(1), equations.Mul(equations.Num(2), equations.Num(3))))
+
+	if result.String() != "7.000000" {
+		t.Fatalf("expected %v to be 7.000000", result)
+	}
+	if len(steps) != 2 || steps[0].Rule != "mulMatcher" || steps[1].Rule != "addMatcher" {
+		t.Fatalf("expected a mulMatcher and an addMatcher step, got %v", steps)
+	}
+	if steps[0].String() != "mulMatcher: (2.000000 * 3.000000) -> 6.000000" {
+		t.Fatalf("expected %v to be mulMatcher: (2.000000 * 3.000000) -> 6.000000", steps[0])
+	}
+	if steps[1].Tree.String() != "(1.000000 + 6.000000)" {
+		t.Fatalf("expected %v to be (1.000000 + 6.000000)", steps[1].Tree)
+	}
+}
+
+func TestTrace_heldNodes(t *testing.T) {
+	expansion, _ := equations.Series(equations.Ln(equations.Var(1, "x", 1)), "x", 1, 2)
+	simplifier := equations.NewSimplifier()
+
+	result, steps := simplifier.Trace(expansion.Polynomial)
+	if expected := simplifier.Simplify(expansion.Polynomial); result.String() != expected.String() {
+		t.Fatalf("expected %v to be %v", result, expected)
+	}
+	if len(steps) == 0 {
+		t.Fatal("expected the held offsets to be simplified")
+	}
+	for _, step := range steps {
+		if step.Rule == "binomial1Matcher" {
+			t.Fatalf("expected held powers not to be expanded, got %v", step)
+		}
+	}
+}
+
+func TestDotTrace(t *testing.T) {
+	_, steps := equations.NewSimplifier().Trace(equations.Add(equations.Num(1), equations.Mul(equations.Num(2), equations.Num(3))))
+	result := equations.DotTrace(steps)
+
+	if !strings.Contains(result, `label="1: mulMatcher"`) || !strings.Contains(result, `label="2: addMatcher"`) {
+		t.Fatalf("expected %v to label both steps", result)
+	}
+	if !strings.Contains(result, `s0n0 [label="+"];`) || !strings.Contains(result, `s0n2 [label="*", style=filled, fillcolor=lightcoral];`) {
+		t.Fatalf("expected %v to highlight only the multiplication in the first step", result)
+	}
+	if !strings.Contains(result, `s1n0 [label="+", style=filled, fillcolor=lightcoral];`) {
+		t.Fatalf("expected %v to highlight the whole tree in the second step", result)
+	}
+}
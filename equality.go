@@ -0,0 +1,169 @@
+package equations
+
+import (
+	"encoding/binary"
+	"hash/fnv"
+	"math"
+)
+
+func sameNumber(a, b value) bool {
+	if a.precise != nil && b.precise != nil {
+		return a.precise.Cmp(b.precise) == 0 && a.imaginary == b.imaginary
+	}
+	return a.number == b.number && a.imaginary == b.imaginary
+}
+
+func Equal(a, b value) bool {
+	if a.op != b.op {
+		return false
+	}
+	switch a.op {
+	case "num":
+		return sameNumber(a, b)
+	case "var":
+		return sameNumber(a, b) && a.name == b.name && a.exponent == b.exponent
+	case "const":
+		return a.name == b.name && a.number == b.number
+	}
+	return equalChild(a.left, b.left, Equal) && equalChild(a.right, b.right, Equal)
+}
+
+func equalChild(a, b *value, equal func(value, value) bool) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a == b || equal(*a, *b)
+}
+
+func commutative(op string) bool {
+	return op == "+" || op == "*"
+}
+
+func operands(val value, op string) []value {
+	if val.op != op {
+		return []value{val}
+	}
+	return append(operands(*val.left, op), operands(*val.right, op)...)
+}
+
+func EqualCommutative(a, b value) bool {
+	if a.op != b.op {
+		return false
+	}
+	if !commutative(a.op) {
+		if a.op == "num" || a.op == "var" || a.op == "const" {
+			return Equal(a, b)
+		}
+		return equalChild(a.left, b.left, EqualCommutative) && equalChild(a.right, b.right, EqualCommutative)
+	}
+
+	left, right := operands(a, a.op), operands(b, b.op)
+	if len(left) != len(right) {
+		return false
+	}
+	used := make([]bool, len(right))
+	for _, l := range left {
+		found := false
+		for j, r := range right {
+			if !used[j] && EqualCommutative(l, r) {
+				used[j], found = true, true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+func floatBits(f float64) uint64 {
+	if f == 0 {
+		return 0
+	}
+	return math.Float64bits(f)
+}
+
+func mix(h uint64) uint64 {
+	h ^= h >> 33
+	h *= 0xff51afd7ed558ccd
+	h ^= h >> 33
+	h *= 0xc4ceb9fe1a85ec53
+	h ^= h >> 33
+	return h
+}
+
+func Hash(val value) uint64 {
+	hasher := fnv.New64a()
+	hasher.Write([]byte(val.op))
+	hasher.Write([]byte{0})
+
+	var buffer [8]byte
+	write := func(n uint64) {
+		binary.LittleEndian.PutUint64(buffer[:], n)
+		hasher.Write(buffer[:])
+	}
+
+	switch {
+	case val.op == "num":
+		number := val.number
+		if val.precise != nil {
+			number, _ = val.precise.Float64()
+		}
+		write(floatBits(number))
+		write(floatBits(val.imaginary))
+	case val.op == "var":
+		write(floatBits(val.number))
+		write(floatBits(val.exponent))
+		hasher.Write([]byte(val.name))
+	case val.op == "const":
+		write(floatBits(val.number))
+		hasher.Write([]byte(val.name))
+	case commutative(val.op):
+		var sum uint64
+		for _, operand := range operands(val, val.op) {
+			sum += mix(Hash(operand))
+		}
+		write(sum)
+	default:
+		for _, child := range []*value{val.left, val.right} {
+			if child != nil {
+				write(Hash(*child))
+			}
+		}
+	}
+	return hasher.Sum64()
+}
+
+type Interner struct {
+	table map[uint64][]*value
+	size  int
+}
+
+func NewInterner() *Interner {
+	return &Interner{table: make(map[uint64][]*value)}
+}
+
+func (in *Interner) Intern(val value) *value {
+	if val.left != nil {
+		val.left = in.Intern(*val.left)
+	}
+	if val.right != nil {
+		val.right = in.Intern(*val.right)
+	}
+
+	h := Hash(val)
+	for _, candidate := range in.table[h] {
+		if candidate.held == val.held && Equal(*candidate, val) {
+			return candidate
+		}
+	}
+	canonical := &val
+	in.table[h] = append(in.table[h], canonical)
+	in.size++
+	return canonical
+}
+
+func (in *Interner) Size() int {
+	return in.size
+}
@@ -0,0 +1,75 @@
+package equations_test
+
+import (
+	"testing"
+
+	"github.com/gossie/equations"
+)
+
+func TestEqual(t *testing.T) {
+	a := equations.Add(equations.Var(2, "x", 1), equations.Mul(equations.Num(3), equations.Var(1, "y", 2)))
+	b := equations.Add(equations.Var(2, "x", 1), equations.Mul(equations.Num(3), equations.Var(1, "y", 2)))
+	swapped := equations.Add(equations.Mul(equations.Var(1, "y", 2), equations.Num(3)), equations.Var(2, "x", 1))
+
+	if !equations.Equal(a, b) {
+		t.Fatalf("expected %v to equal %v", a, b)
+	}
+	if equations.Equal(a, swapped) {
+		t.Fatalf("expected %v not to equal %v structurally", a, swapped)
+	}
+	if !equations.EqualCommutative(a, swapped) {
+		t.Fatalf("expected %v to equal %v up to commutativity", a, swapped)
+	}
+}
+
+func TestEqualCommutative_associativity(t *testing.T) {
+	a := equations.Add(equations.Add(equations.Var(1, "a", 1), equations.Var(1, "b", 1)), equations.Var(1, "c", 1))
+	b := equations.Add(equations.Var(1, "c", 1), equations.Add(equations.Var(1, "b", 1), equations.Var(1, "a", 1)))
+	c := equations.Add(equations.Var(1, "c", 1), equations.Add(equations.Var(1, "b", 1), equations.Var(1, "b", 1)))
+
+	if !equations.EqualCommutative(a, b) {
+		t.Fatalf("expected %v to equal %v", a, b)
+	}
+	if equations.EqualCommutative(a, c) {
+		t.Fatalf("expected %v not to equal %v", a, c)
+	}
+	if equations.EqualCommutative(equations.Sub(equations.Var(1, "a", 1), equations.Var(1, "b", 1)), equations.Sub(equations.Var(1, "b", 1), equations.Var(1, "a", 1))) {
+		t.Fatal("expected subtraction not to commute")
+	}
+}
+
+func TestHash(t *testing.T) {
+	a := equations.Mul(equations.Add(equations.Var(1, "x", 1), equations.Num(1)), equations.Sin(equations.Var(1, "y", 1)))
+	b := equations.Mul(equations.Sin(equations.Var(1, "y", 1)), equations.Add(equations.Num(1), equations.Var(1, "x", 1)))
+	c := equations.Mul(equations.Add(equations.Var(1, "x", 1), equations.Num(2)), equations.Sin(equations.Var(1, "y", 1)))
+
+	if equations.Hash(a) != equations.Hash(b) {
+		t.Fatalf("expected %v and %v to hash equally", a, b)
+	}
+	if equations.Hash(a) == equations.Hash(c) {
+		t.Fatalf("expected %v and %v to hash differently", a, c)
+	}
+	if equations.Hash(equations.Sub(equations.Num(1), equations.Num(2))) == equations.Hash(equations.Sub(equations.Num(2), equations.Num(1))) {
+		t.Fatal("expected subtraction to hash in order")
+	}
+	if equations.Hash(equations.Num(1)) != 0xcd9418ea96108634 {
+		t.Fatalf("expected the hash of 1 to be stable, got %#x", equations.Hash(equations.Num(1)))
+	}
+}
+
+func TestInterner(t *testing.T) {
+	shared := equations.Add(equations.Var(1, "x", 1), equations.Num(1))
+	expression := equations.Add(equations.Mul(shared, shared), equations.Div(equations.Num(1), equations.Add(equations.Var(1, "x", 1), equations.Num(1))))
+
+	interner := equations.NewInterner()
+	first := interner.Intern(equations.Add(equations.Var(1, "x", 1), equations.Num(1)))
+	second := interner.Intern(equations.Add(equations.Var(1, "x", 1), equations.Num(1)))
+	if first != second {
+		t.Fatal("expected equal values to be interned to the same node")
+	}
+
+	interner.Intern(expression)
+	if interner.Size() != 6 {
+		t.Fatalf("expected 6 distinct nodes, got %v", interner.Size())
+	}
+}
@@ -3,17 +3,12 @@ package equations
 import (
 	"errors"
 	"fmt"
+	"math/big"
+	"strings"
 )
 
 type BinaryOp func(value, value) value
 
-var operators = map[string]BinaryOp{
-	"+": Add,
-	"*": Mul,
-	"-": Sub,
-	"/": Div,
-}
-
 var rightComplements = map[string]string{
 	"+": "-",
 	"*": "/",
@@ -41,9 +36,28 @@ func findValue(val *value, name string) (*value, path, path, error) {
 		return val, make(path, 0), append(make(path, 0), &opValuePair{"/", Num(val.number), false}), nil
 	}
 
+	if containsMatrix(*val) {
+		if containsVariable(*val, name) {
+			return nil, nil, nil, &isolationError{name, "matrix"}
+		}
+		return nil, nil, nil, errors.New("variable " + name + " not found")
+	}
+
+	if _, unary := functions[val.op]; unary {
+		return findValueInFunction(val, name)
+	}
+
+	if val.op == "^" {
+		return findValueInPower(val, name)
+	}
+
+	var blocked error
 	if val.left != nil || val.right != nil {
 		if val.left != nil {
 			found, pathToValue, complementaryPath, err := findValue(val.left, name)
+			if isIsolationError(err) {
+				blocked = err
+			}
 			if err == nil {
 				op := rightComplements[val.op]
 				pathToValue = append(pathToValue, &opValuePair{op, *val.left, val.op == "-" || val.op == "/"})
@@ -54,6 +68,9 @@ func findValue(val *value, name string) (*value, path, path, error) {
 
 		if val.right != nil {
 			found, pathToValue, complementaryPath, err := findValue(val.right, name)
+			if isIsolationError(err) {
+				blocked = err
+			}
 			if err == nil {
 				op := leftComplements[val.op]
 				pathToValue = append(pathToValue, &opValuePair{op, *val.right, false})
@@ -63,6 +80,9 @@ func findValue(val *value, name string) (*value, path, path, error) {
 		}
 	}
 
+	if blocked != nil {
+		return nil, nil, nil, blocked
+	}
 	return nil, nil, nil, errors.New("variable " + name + " not found")
 }
 
@@ -98,50 +118,57 @@ func SolveTo(eq *equation, varName string) (*value, error) {
 	right, rightPath, rightComplementaryPath, errRight := findValue(&eq.right, varName)
 
 	if left != nil && right != nil {
-		eq := NewEquation(processPathElement(rightPath[len(rightPath)-1], eq.left), processPathElement(rightPath[len(rightPath)-1], eq.right))
+		element := rightPath[len(rightPath)-1]
+		if !element.eliminates() {
+			return nil, &SolveError{errors.New(varName + " could not be eliminated from both sides"), eq}
+		}
+		eq := NewEquation(processPathElement(element, eq.left), processPathElement(element, eq.right))
 		eq = optimize(&eq)
 		return SolveTo(&eq, varName)
 	}
 
 	if errLeft != nil && errRight != nil {
+		if isIsolationError(errLeft) || isIsolationError(errRight) {
+			return nil, &SolveError{blockingError(errLeft, errRight), eq}
+		}
 		return nil, &SolveError{errors.New(varName + " could not be found"), eq}
 	}
 
+	var result value
 	if left != nil {
-		return processPath(eq.right, leftComplementaryPath), nil
+		result = *processPath(eq.right, leftComplementaryPath)
 	} else {
-		return processPath(eq.left, rightComplementaryPath), nil
+		result = *processPath(eq.left, rightComplementaryPath)
 	}
+	result = simplifyUnits(result)
+	return &result, nil
 }
 
 func Set(e *equation, varName string, val value) equation {
-	newLeft := insert(e.left, varName, val)
-	newRight := insert(e.right, varName, val)
-	return NewEquation(newLeft, newRight)
+	return SubstituteEquation(e, map[string]value{varName: val})
 }
 
-func insert(current value, varName string, val value) value {
-	if variable(varName)(&current) {
-		return Mul(Num(current.number), val)
-	}
-
-	if op, present := operators[current.op]; present {
-		return op(insert(*current.left, varName, val), insert(*current.right, varName, val))
-	}
-
-	return current
+func processPath(val value, p path) *value {
+	result, _ := processPathWithConditions(val, p)
+	return result
 }
 
-func processPath(val value, p path) *value {
+func processPathWithConditions(val value, p path) (*value, []Condition) {
+	conditions := make([]Condition, 0)
 	current := val
 	for i := len(p) - 1; i >= 0; i-- {
+		conditions = appendDomainCondition(conditions, p[i], current)
 		current = processPathElement(p[i], current)
 	}
 	result := current.execute()
-	return &result
+	return &result, conditions
 }
 
 func processPathElement(v *opValuePair, current value) value {
+	if _, unary := functions[v.op]; unary {
+		return function(v.op, current)
+	}
+
 	switch v.op {
 	default:
 		panic("unkown operator " + v.op)
@@ -169,9 +196,52 @@ func processPathElement(v *opValuePair, current value) value {
 		} else {
 			return Div(current, v.val)
 		}
+	case "root":
+		return Pow(current, Div(Num(1), v.val))
+	case "log":
+		return Div(Ln(current), Ln(v.val))
 	}
 }
 
+func blockingError(errs ...error) error {
+	for _, err := range errs {
+		if isIsolationError(err) {
+			return err
+		}
+	}
+	return nil
+}
+
+func (v *opValuePair) eliminates() bool {
+	_, binary := rightComplements[v.op]
+	return binary
+}
+
+func findValueInPower(val *value, name string) (*value, path, path, error) {
+	inBase, inExponent := containsVariable(*val.left, name), containsVariable(*val.right, name)
+	switch {
+	case inBase && inExponent:
+		return nil, nil, nil, &isolationError{name, "^"}
+	case inBase:
+		found, pathToValue, complementaryPath, err := findValue(val.left, name)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		pathToValue = append(pathToValue, &opValuePair{"root", *val.right, false})
+		complementaryPath = append(complementaryPath, &opValuePair{"root", *val.right, false})
+		return found, pathToValue, complementaryPath, nil
+	case inExponent:
+		found, pathToValue, complementaryPath, err := findValue(val.right, name)
+		if err != nil {
+			return nil, nil, nil, err
+		}
+		pathToValue = append(pathToValue, &opValuePair{"log", *val.left, false})
+		complementaryPath = append(complementaryPath, &opValuePair{"log", *val.left, false})
+		return found, pathToValue, complementaryPath, nil
+	}
+	return nil, nil, nil, errors.New("variable " + name + " not found")
+}
+
 func (e equation) String() string {
 	return fmt.Sprintf("%v = %v", e.left, e.right)
 }
@@ -180,7 +250,10 @@ type value struct {
 	left, right      *value
 	op               string
 	number, exponent float64
+	imaginary        float64
 	name             string
+	held             bool
+	precise          *big.Float
 }
 
 func (v value) Number() float64 {
@@ -193,37 +266,49 @@ func (v value) Number() float64 {
 }
 
 func (v value) execute() value {
-	if v.left != nil && v.right != nil {
-		l := v.left.execute()
-		r := v.right.execute()
-		v.left = &l
-		v.right = &r
-	}
-
-	for _, pm := range Matchers {
-		if pm.Match(&v) {
-			return pm.Execute().execute()
-		}
-	}
-	return v
+	return defaultSimplifier.Simplify(v)
 }
 
 func (v value) String() string {
+	if _, unary := functions[v.op]; unary {
+		return fmt.Sprintf("%v(%v)", v.op, v.left)
+	}
+
 	switch v.op {
 	default:
 		panic("unknown operator: " + v.op)
 	case "num":
+		if v.imaginary != 0 {
+			return formatComplex(v)
+		}
+		if v.precise != nil {
+			return v.precise.Text('f', -1)
+		}
 		return fmt.Sprintf("%f", v.number)
+	case "const":
+		return v.name
 	case "var":
-		return fmt.Sprintf("%f%v", v.number, v.name)
+		factor := coefficient(v).String()
+		if v.exponent != 1 {
+			return fmt.Sprintf("%v%v^%v", factor, v.name, v.exponent)
+		}
+		return fmt.Sprintf("%v%v", factor, v.name)
 	case "+":
-		return fmt.Sprintf("(%v + %v)", v.left, v.right)
+		return fmt.Sprintf("(%v + %v)", operand(v.left), operand(v.right))
 	case "*":
-		return fmt.Sprintf("(%v * %v)", v.left, v.right)
+		return fmt.Sprintf("(%v * %v)", operand(v.left), operand(v.right))
 	case "-":
-		return fmt.Sprintf("(%v - %v)", v.left, v.right)
+		return fmt.Sprintf("(%v - %v)", operand(v.left), operand(v.right))
 	case "/":
-		return fmt.Sprintf("(%v / %v)", v.left, v.right)
+		return fmt.Sprintf("(%v / %v)", operand(v.left), operand(v.right))
+	case "^":
+		return fmt.Sprintf("(%v ^ %v)", operand(v.left), operand(v.right))
+	case "matrix", "row":
+		items := make([]string, 0)
+		for item := &v; item != nil; item = item.right {
+			items = append(items, item.left.String())
+		}
+		return "[" + strings.Join(items, ", ") + "]"
 	}
 }
 
@@ -0,0 +1,61 @@
+package equations
+
+import (
+	"errors"
+	"math"
+)
+
+func Evaluate(val value, vars map[string]float64) (float64, error) {
+	if f, unary := functions[val.op]; unary {
+		arg, err := Evaluate(*val.left, vars)
+		if err != nil {
+			return 0, err
+		}
+		return f(arg), nil
+	}
+
+	switch val.op {
+	default:
+		return 0, errors.New("cannot evaluate operator " + val.op)
+	case "num":
+		if val.imaginary != 0 {
+			return 0, errors.New("cannot evaluate complex number " + val.String())
+		}
+		return val.number, nil
+	case "const":
+		return val.number, nil
+	case "var":
+		x, present := vars[val.name]
+		if !present {
+			return 0, errors.New("no value for variable " + val.name)
+		}
+		return val.number * math.Pow(x, val.exponent), nil
+	case "+", "-", "*", "/", "^":
+		l, err := Evaluate(*val.left, vars)
+		if err != nil {
+			return 0, err
+		}
+		r, err := Evaluate(*val.right, vars)
+		if err != nil {
+			return 0, err
+		}
+		return evaluateBinary(val.op, l, r), nil
+	}
+}
+
+func evaluateBinary(op string, l, r float64) float64 {
+	switch op {
+	default:
+		panic("unknown operator " + op)
+	case "+":
+		return l + r
+	case "-":
+		return l - r
+	case "*":
+		return l * r
+	case "/":
+		return l / r
+	case "^":
+		return math.Pow(l, r)
+	}
+}
@@ -0,0 +1,37 @@
+package equations_test
+
+import (
+	"testing"
+
+	"github.com/gossie/equations"
+)
+
+func TestEvaluate(t *testing.T) {
+	expression := equations.Add(equations.Var(3, "x", 2), equations.Div(equations.Exp(equations.Num(0)), equations.Var(1, "y", 1)))
+
+	result, err := equations.Evaluate(expression, map[string]float64{"x": 2, "y": 4})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != 12.25 {
+		t.Fatalf("expected %v to be 12.25", result)
+	}
+}
+
+func TestEvaluate_missingVariable(t *testing.T) {
+	expression := equations.Add(equations.Var(3, "x", 2), equations.Var(1, "y", 1))
+
+	_, err := equations.Evaluate(expression, map[string]float64{"x": 2})
+	if err == nil || err.Error() != "no value for variable y" {
+		t.Fatalf("expected error %v to be 'no value for variable y'", err)
+	}
+}
+
+func TestEvaluate_complex(t *testing.T) {
+	expression := equations.Add(equations.Num(1), equations.I())
+
+	_, err := equations.Evaluate(expression, map[string]float64{})
+	if err == nil {
+		t.Fatal("expected an error for a complex number")
+	}
+}
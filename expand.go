@@ -0,0 +1,169 @@
+package equations
+
+import (
+	"math"
+	"sort"
+)
+
+func Expand(expr value) value {
+	if p, err := toPolynomial(expr); err == nil {
+		return fromPolynomial(p)
+	}
+	return sum(expandTerms(expr)).execute()
+}
+
+func expandTerms(val value) []value {
+	val.held = false
+	switch val.op {
+	case "+":
+		return append(expandTerms(*val.left), expandTerms(*val.right)...)
+	case "-":
+		terms := expandTerms(*val.left)
+		for _, t := range expandTerms(*val.right) {
+			terms = append(terms, negate(t))
+		}
+		return terms
+	case "*":
+		terms := make([]value, 0)
+		for _, l := range expandTerms(*val.left) {
+			for _, r := range expandTerms(*val.right) {
+				terms = append(terms, Mul(l, r))
+			}
+		}
+		return terms
+	case "/":
+		denominator := Expand(*val.right)
+		terms := make([]value, 0)
+		for _, t := range expandTerms(*val.left) {
+			terms = append(terms, Div(t, denominator))
+		}
+		return terms
+	case "^":
+		exponent := val.right.execute()
+		if exponent.op == "num" && exponent.number >= 1 && exponent.number == math.Trunc(exponent.number) {
+			base := expandTerms(*val.left)
+			terms := base
+			for i := 1; i < int(exponent.number); i++ {
+				terms = expandTerms(Mul(sum(terms), sum(base)))
+			}
+			return terms
+		}
+		return []value{Pow(Expand(*val.left), exponent)}
+	}
+
+	if _, unary := functions[val.op]; unary {
+		return []value{function(val.op, Expand(*val.left))}
+	}
+	return []value{val}
+}
+
+func negate(val value) value {
+	switch val.op {
+	case "num":
+		return Num(-val.number)
+	case "var":
+		return Var(-val.number, val.name, val.exponent)
+	}
+	return Mul(Num(-1), val)
+}
+
+func sum(terms []value) value {
+	if len(terms) == 0 {
+		return Num(0)
+	}
+	result := terms[0]
+	for _, t := range terms[1:] {
+		result = Add(result, t)
+	}
+	return result
+}
+
+func Collect(expr value, varName string) value {
+	powers, _ := collectPowers(expr, varName)
+
+	exponents := make([]float64, 0, len(powers))
+	for exponent := range powers {
+		exponents = append(exponents, exponent)
+	}
+	sort.Sort(sort.Reverse(sort.Float64Slice(exponents)))
+
+	terms := make([]value, 0, len(exponents))
+	for _, exponent := range exponents {
+		coefficient := powers[exponent]
+		switch {
+		case exponent == 0:
+			terms = append(terms, coefficient)
+		case coefficient.op == "num":
+			terms = append(terms, Var(coefficient.number, varName, exponent))
+		default:
+			terms = append(terms, Mul(coefficient, Var(1, varName, exponent)))
+		}
+	}
+	return sum(terms)
+}
+
+func collectPowers(expr value, varName string) (map[float64]value, bool) {
+	if p, err := toPolynomial(expr); err == nil {
+		grouped := make(map[int]polynomial)
+		for _, t := range p.terms {
+			exponent := t.monomial[varName]
+			m := make(monomial)
+			for name, e := range t.monomial {
+				if name != varName {
+					m[name] = e
+				}
+			}
+			if _, present := grouped[exponent]; !present {
+				grouped[exponent] = newPolynomial()
+			}
+			grouped[exponent].addTerm(t.coefficient, m)
+		}
+
+		powers := make(map[float64]value, len(grouped))
+		for exponent, coefficient := range grouped {
+			powers[float64(exponent)] = fromPolynomial(coefficient)
+		}
+		return powers, true
+	}
+
+	polynomial := true
+	grouped := make(map[float64][]value)
+	for _, t := range expandTerms(expr) {
+		exponent, coefficient, ok := splitPower(t, varName)
+		polynomial = polynomial && ok
+		grouped[exponent] = append(grouped[exponent], coefficient)
+	}
+
+	powers := make(map[float64]value, len(grouped))
+	for exponent, coefficients := range grouped {
+		coefficient := sum(coefficients).execute()
+		if coefficient.op == "num" && coefficient.number == 0 {
+			continue
+		}
+		powers[exponent] = coefficient
+	}
+	return powers, polynomial
+}
+
+func splitPower(term value, varName string) (float64, value, bool) {
+	switch term.op {
+	case "var":
+		if term.name == varName {
+			return term.exponent, Num(term.number), true
+		}
+	case "*":
+		le, lc, lok := splitPower(*term.left, varName)
+		re, rc, rok := splitPower(*term.right, varName)
+		if lok && rok {
+			return le + re, Mul(lc, rc).execute(), true
+		}
+	case "/":
+		if !containsVariable(*term.right, varName) {
+			e, c, ok := splitPower(*term.left, varName)
+			if ok {
+				return e, Div(c, *term.right).execute(), true
+			}
+		}
+	}
+	return 0, term, !containsVariable(term, varName)
+}
@@ -0,0 +1,52 @@
+package equations_test
+
+import (
+	"testing"
+
+	"github.com/gossie/equations"
+)
+
+func TestExpand_product(t *testing.T) {
+	product := equations.Mul(equations.Add(equations.Var(1, "x", 1), equations.Num(1)), equations.Add(equations.Var(1, "x", 1), equations.Num(2)))
+
+	expanded := equations.Expand(product)
+	if expanded.String() != "((1.000000x^2 + 3.000000x) + 2.000000)" {
+		t.Fatalf("expected %v to be ((1.000000x^2 + 3.000000x) + 2.000000)", expanded)
+	}
+}
+
+func TestExpand_power(t *testing.T) {
+	power := equations.Pow(equations.Sub(equations.Var(1, "x", 1), equations.Var(1, "y", 1)), equations.Num(3))
+
+	expanded := equations.Expand(power)
+	if expanded.String() != "(((1.000000x^3 + (-3.000000x^2 * 1.000000y)) + (3.000000x * 1.000000y^2)) + -1.000000y^3)" {
+		t.Fatalf("expected %v to be (((1.000000x^3 + (-3.000000x^2 * 1.000000y)) + (3.000000x * 1.000000y^2)) + -1.000000y^3)", expanded)
+	}
+}
+
+func TestExpand_nonPolynomial(t *testing.T) {
+	product := equations.Mul(equations.Var(2, "x", 1), equations.Add(equations.Sin(equations.Var(1, "y", 1)), equations.Num(3)))
+
+	expanded := equations.Expand(product)
+	if expanded.String() != "((2.000000x * sin(1.000000y)) + 6.000000x)" {
+		t.Fatalf("expected %v to be ((2.000000x * sin(1.000000y)) + 6.000000x)", expanded)
+	}
+}
+
+func TestCollect(t *testing.T) {
+	expression := equations.Add(equations.Mul(equations.Var(1, "x", 1), equations.Add(equations.Var(1, "y", 1), equations.Num(3))), equations.Mul(equations.Var(2, "x", 2), equations.Var(1, "y", 1)))
+
+	collected := equations.Collect(expression, "x")
+	if collected.String() != "((2.000000y * 1.000000x^2) + ((1.000000y + 3.000000) * 1.000000x))" {
+		t.Fatalf("expected %v to be ((2.000000y * 1.000000x^2) + ((1.000000y + 3.000000) * 1.000000x))", collected)
+	}
+}
+
+func TestCollect_nonPolynomialCoefficients(t *testing.T) {
+	expression := equations.Add(equations.Mul(equations.Var(1, "x", 1), equations.Sin(equations.Var(1, "y", 1))), equations.Add(equations.Var(2, "x", 1), equations.Num(1)))
+
+	collected := equations.Collect(expression, "x")
+	if collected.String() != "(((sin(1.000000y) + 2.000000) * 1.000000x) + 1.000000)" {
+		t.Fatalf("expected %v to be (((sin(1.000000y) + 2.000000) * 1.000000x) + 1.000000)", collected)
+	}
+}
@@ -0,0 +1,537 @@
+package equations
+
+import (
+	"math/big"
+	"sort"
+)
+
+type upoly []*big.Rat
+
+func (p upoly) trim() upoly {
+	for len(p) > 0 && p[len(p)-1].Sign() == 0 {
+		p = p[:len(p)-1]
+	}
+	return p
+}
+
+func (p upoly) degree() int {
+	return len(p.trim()) - 1
+}
+
+func (p upoly) lead() *big.Rat {
+	return p[len(p)-1]
+}
+
+func (p upoly) isConstant() bool {
+	return p.degree() <= 0
+}
+
+func (p upoly) scale(c *big.Rat) upoly {
+	result := make(upoly, len(p))
+	for i, a := range p {
+		result[i] = new(big.Rat).Mul(a, c)
+	}
+	return result.trim()
+}
+
+func (p upoly) sub(q upoly) upoly {
+	n := len(p)
+	if len(q) > n {
+		n = len(q)
+	}
+	result := make(upoly, n)
+	for i := range result {
+		result[i] = new(big.Rat)
+		if i < len(p) {
+			result[i].Add(result[i], p[i])
+		}
+		if i < len(q) {
+			result[i].Sub(result[i], q[i])
+		}
+	}
+	return result.trim()
+}
+
+func (p upoly) mul(q upoly) upoly {
+	if len(p) == 0 || len(q) == 0 {
+		return upoly{}
+	}
+	result := make(upoly, len(p)+len(q)-1)
+	for i := range result {
+		result[i] = new(big.Rat)
+	}
+	for i, a := range p {
+		for j, b := range q {
+			result[i+j].Add(result[i+j], new(big.Rat).Mul(a, b))
+		}
+	}
+	return result.trim()
+}
+
+func (p upoly) divmod(q upoly) (upoly, upoly) {
+	q = q.trim()
+	remainder := append(upoly(nil), p.trim()...)
+	if len(remainder) < len(q) {
+		return upoly{}, remainder
+	}
+	quotient := make(upoly, len(remainder)-len(q)+1)
+	for i := range quotient {
+		quotient[i] = new(big.Rat)
+	}
+	for len(remainder) >= len(q) && len(remainder) > 0 {
+		shift := len(remainder) - len(q)
+		factor := new(big.Rat).Quo(remainder.lead(), q.lead())
+		quotient[shift] = factor
+		subtrahend := make(upoly, shift)
+		for i := range subtrahend {
+			subtrahend[i] = new(big.Rat)
+		}
+		subtrahend = append(subtrahend, q.scale(factor)...)
+		remainder = remainder.sub(subtrahend)
+	}
+	return quotient.trim(), remainder
+}
+
+func (p upoly) monic() upoly {
+	p = p.trim()
+	if len(p) == 0 {
+		return p
+	}
+	return p.scale(new(big.Rat).Inv(p.lead()))
+}
+
+func (p upoly) derivative() upoly {
+	if len(p) <= 1 {
+		return upoly{}
+	}
+	result := make(upoly, len(p)-1)
+	for i := 1; i < len(p); i++ {
+		result[i-1] = new(big.Rat).Mul(p[i], big.NewRat(int64(i), 1))
+	}
+	return result.trim()
+}
+
+func (p upoly) eval(x *big.Rat) *big.Rat {
+	result := new(big.Rat)
+	for i := len(p) - 1; i >= 0; i-- {
+		result.Mul(result, x)
+		result.Add(result, p[i])
+	}
+	return result
+}
+
+func ugcd(a, b upoly) upoly {
+	a, b = a.trim(), b.trim()
+	for len(b) > 0 {
+		_, r := a.divmod(b)
+		a, b = b, r
+	}
+	return a.monic()
+}
+
+func (p polynomial) univariate(name string) upoly {
+	result := make(upoly, p.degree(name)+1)
+	for i := range result {
+		result[i] = new(big.Rat)
+	}
+	for _, t := range p.terms {
+		result[t.monomial[name]].Add(result[t.monomial[name]], t.coefficient)
+	}
+	return result.trim()
+}
+
+func fromUnivariate(p upoly, name string) polynomial {
+	result := newPolynomial()
+	for i, c := range p {
+		if i == 0 {
+			result.addTerm(c, monomial{})
+		} else {
+			result.addTerm(c, monomial{name: i})
+		}
+	}
+	return result
+}
+
+type factor struct {
+	base         polynomial
+	multiplicity int
+}
+
+func Factor(expr value) (value, error) {
+	p, err := toPolynomial(expr)
+	if err != nil {
+		return value{}, err
+	}
+	if p.isZero() {
+		return Num(0), nil
+	}
+	return productOf(factorize(p)), nil
+}
+
+func factorize(p polynomial) (*big.Rat, []factor) {
+	content, monomialFactor, primitive := p.content()
+	factors := make([]factor, 0)
+	if len(monomialFactor) > 0 {
+		leading := newPolynomial()
+		leading.addTerm(content, monomialFactor)
+		factors = append(factors, factor{leading, 1})
+		content = big.NewRat(1, 1)
+	}
+
+	vars := primitive.variables()
+	if len(vars) == 1 {
+		for _, f := range factorUnivariate(primitive.univariate(vars[0])) {
+			factors = append(factors, factorByPatterns(fromUnivariate(f.base, vars[0]), f.multiplicity)...)
+		}
+	} else if len(vars) > 1 {
+		split := make([]factor, 0)
+		for _, f := range squareFreeMultivariate(primitive) {
+			split = append(split, factorByPatterns(f.base, f.multiplicity)...)
+		}
+		expanded := one()
+		for _, f := range split {
+			expanded = expanded.mul(f.base.pow(f.multiplicity))
+		}
+		if quotient, exact := divide(primitive, expanded); exact {
+			if c, ok := quotient.constant(); ok {
+				content.Mul(content, c)
+			}
+		}
+		factors = append(factors, split...)
+	}
+	return content, factors
+}
+
+func squareFreeMultivariate(p polynomial) []factor {
+	x := p.variables()[0]
+	content, q := p.primitive(x)
+	factors := make([]factor, 0)
+	if len(content.variables()) > 0 {
+		_, parts := factorize(content)
+		factors = append(factors, parts...)
+	}
+
+	c := polynomialGCD(q, q.derivative(x))
+	w, _ := divide(q, c)
+	i := 1
+	for ; len(c.variables()) > 0; i++ {
+		y := polynomialGCD(w, c)
+		z, _ := divide(w, y)
+		if len(z.variables()) > 0 {
+			factors = append(factors, factor{integralPolynomial(z), i})
+		}
+		w = y
+		c, _ = divide(c, y)
+	}
+	if len(w.variables()) > 0 {
+		factors = append(factors, factor{integralPolynomial(w), i})
+	}
+	return factors
+}
+
+func (p polynomial) derivative(name string) polynomial {
+	result := newPolynomial()
+	for _, t := range p.terms {
+		e := t.monomial[name]
+		if e == 0 {
+			continue
+		}
+		m := make(monomial)
+		for other, f := range t.monomial {
+			m[other] = f
+		}
+		m[name] = e - 1
+		if m[name] == 0 {
+			delete(m, name)
+		}
+		result.addTerm(new(big.Rat).Mul(t.coefficient, big.NewRat(int64(e), 1)), m)
+	}
+	return result
+}
+
+func integralPolynomial(p polynomial) polynomial {
+	content, _, _ := p.content()
+	return p.scale(new(big.Rat).Inv(content))
+}
+
+func (p polynomial) content() (*big.Rat, monomial, polynomial) {
+	numerators := big.NewInt(0)
+	denominators := big.NewInt(1)
+	minimal := monomial(nil)
+	for _, t := range p.terms {
+		numerators.GCD(nil, nil, numerators, new(big.Int).Abs(t.coefficient.Num()))
+		denominators = lcm(denominators, t.coefficient.Denom())
+		if minimal == nil {
+			minimal = make(monomial)
+			for name, e := range t.monomial {
+				minimal[name] = e
+			}
+			continue
+		}
+		for name, e := range minimal {
+			if t.monomial[name] < e {
+				minimal[name] = t.monomial[name]
+			}
+		}
+	}
+	for name, e := range minimal {
+		if e == 0 {
+			delete(minimal, name)
+		}
+	}
+
+	content := new(big.Rat).SetFrac(numerators, denominators)
+	if p.sortedTerms()[0].coefficient.Sign() < 0 {
+		content.Neg(content)
+	}
+
+	primitive := newPolynomial()
+	for _, t := range p.terms {
+		m := make(monomial)
+		for name, e := range t.monomial {
+			if e-minimal[name] > 0 {
+				m[name] = e - minimal[name]
+			}
+		}
+		primitive.addTerm(new(big.Rat).Quo(t.coefficient, content), m)
+	}
+	return content, minimal, primitive
+}
+
+func lcm(a, b *big.Int) *big.Int {
+	gcd := new(big.Int).GCD(nil, nil, a, b)
+	return new(big.Int).Div(new(big.Int).Mul(a, b), gcd)
+}
+
+type ufactor struct {
+	base         upoly
+	multiplicity int
+}
+
+func factorUnivariate(p upoly) []ufactor {
+	factors := make([]ufactor, 0)
+	for _, sf := range squareFree(p) {
+		remaining := sf.base
+		for _, root := range rationalRoots(remaining) {
+			linear := upoly{new(big.Rat).Neg(root), big.NewRat(1, 1)}
+			remaining, _ = remaining.divmod(linear)
+			factors = append(factors, ufactor{integral(linear), sf.multiplicity})
+		}
+		if !remaining.isConstant() {
+			factors = append(factors, ufactor{integral(remaining), sf.multiplicity})
+		}
+	}
+	return factors
+}
+
+func squareFree(p upoly) []ufactor {
+	factors := make([]ufactor, 0)
+	c := ugcd(p, p.derivative())
+	w, _ := p.monic().divmod(c)
+	i := 1
+	for ; !c.isConstant(); i++ {
+		y := ugcd(w, c)
+		z, _ := w.divmod(y)
+		if !z.isConstant() {
+			factors = append(factors, ufactor{z, i})
+		}
+		w = y
+		c, _ = c.divmod(y)
+	}
+	if !w.isConstant() {
+		factors = append(factors, ufactor{w, i})
+	}
+	return factors
+}
+
+func integral(p upoly) upoly {
+	denominators := big.NewInt(1)
+	numerators := big.NewInt(0)
+	for _, c := range p {
+		denominators = lcm(denominators, c.Denom())
+		numerators.GCD(nil, nil, numerators, new(big.Int).Abs(c.Num()))
+	}
+	factor := new(big.Rat).SetFrac(denominators, numerators)
+	if p.lead().Sign() < 0 {
+		factor.Neg(factor)
+	}
+	return p.scale(factor)
+}
+
+func rationalRoots(p upoly) []*big.Rat {
+	roots := make([]*big.Rat, 0)
+	p = integral(p)
+	if p.degree() < 1 {
+		return roots
+	}
+	for p[0].Sign() == 0 {
+		roots = append(roots, new(big.Rat))
+		p = p[1:]
+	}
+
+	constant, leading := p[0].Num(), p.lead().Num()
+	if constant.BitLen() > 40 || leading.BitLen() > 40 {
+		return roots
+	}
+	for _, numerator := range divisors(constant.Int64()) {
+		for _, denominator := range divisors(leading.Int64()) {
+			for _, sign := range []int64{1, -1} {
+				candidate := big.NewRat(sign*numerator, denominator)
+				for p.degree() >= 1 && p.eval(candidate).Sign() == 0 {
+					if !containsRat(roots, candidate) {
+						roots = append(roots, candidate)
+					}
+					p, _ = p.divmod(upoly{new(big.Rat).Neg(candidate), big.NewRat(1, 1)})
+				}
+			}
+		}
+	}
+	return roots
+}
+
+func containsRat(rats []*big.Rat, r *big.Rat) bool {
+	for _, candidate := range rats {
+		if candidate.Cmp(r) == 0 {
+			return true
+		}
+	}
+	return false
+}
+
+func divisors(n int64) []int64 {
+	if n < 0 {
+		n = -n
+	}
+	result := make([]int64, 0)
+	for d := int64(1); d*d <= n; d++ {
+		if n%d == 0 {
+			result = append(result, d)
+			if d*d != n {
+				result = append(result, n/d)
+			}
+		}
+	}
+	sort.Slice(result, func(i, j int) bool { return result[i] < result[j] })
+	return result
+}
+
+func factorByPatterns(p polynomial, multiplicity int) []factor {
+	if a, b, ok := differenceOfSquares(p); ok {
+		return append(factorByPatterns(a.add(b), multiplicity), factorByPatterns(a.sub(b), multiplicity)...)
+	}
+	if root, ok := perfectSquare(p); ok {
+		return factorByPatterns(root, 2*multiplicity)
+	}
+	return []factor{{p, multiplicity}}
+}
+
+func differenceOfSquares(p polynomial) (polynomial, polynomial, bool) {
+	if len(p.terms) != 2 {
+		return polynomial{}, polynomial{}, false
+	}
+	terms := p.sortedTerms()
+	if terms[0].coefficient.Sign() == terms[1].coefficient.Sign() {
+		return polynomial{}, polynomial{}, false
+	}
+	if terms[0].coefficient.Sign() < 0 {
+		terms[0], terms[1] = terms[1], terms[0]
+	}
+	a, okA := squareRootOfTerm(terms[0], false)
+	b, okB := squareRootOfTerm(terms[1], true)
+	return a, b, okA && okB
+}
+
+func perfectSquare(p polynomial) (polynomial, bool) {
+	if len(p.terms) != 3 {
+		return polynomial{}, false
+	}
+	terms := p.sortedTerms()
+	squares := make([]polynomial, 0, 2)
+	var middle polyTerm
+	for _, t := range terms {
+		if root, ok := squareRootOfTerm(t, false); ok && t.coefficient.Sign() > 0 && len(squares) < 2 {
+			squares = append(squares, root)
+		} else {
+			middle = t
+		}
+	}
+	if len(squares) != 2 || middle.coefficient == nil {
+		return polynomial{}, false
+	}
+
+	candidate := squares[0].add(squares[1])
+	if candidate.pow(2).sub(p).isZero() {
+		return candidate, true
+	}
+	candidate = squares[0].sub(squares[1])
+	if candidate.pow(2).sub(p).isZero() {
+		return candidate, true
+	}
+	return polynomial{}, false
+}
+
+func squareRootOfTerm(t polyTerm, absolute bool) (polynomial, bool) {
+	c := new(big.Rat).Set(t.coefficient)
+	if absolute {
+		c.Abs(c)
+	}
+	if c.Sign() < 0 {
+		return polynomial{}, false
+	}
+	numerator, denominator := new(big.Int).Sqrt(c.Num()), new(big.Int).Sqrt(c.Denom())
+	if new(big.Int).Mul(numerator, numerator).Cmp(c.Num()) != 0 || new(big.Int).Mul(denominator, denominator).Cmp(c.Denom()) != 0 {
+		return polynomial{}, false
+	}
+	m := make(monomial)
+	for name, e := range t.monomial {
+		if e%2 != 0 {
+			return polynomial{}, false
+		}
+		m[name] = e / 2
+	}
+	root := newPolynomial()
+	root.addTerm(new(big.Rat).SetFrac(numerator, denominator), m)
+	return root, true
+}
+
+func productOf(content *big.Rat, factors []factor) value {
+	result := make([]value, 0, len(factors)+1)
+	if content.Cmp(big.NewRat(1, 1)) != 0 || len(factors) == 0 {
+		result = append(result, rationalValue(content))
+	}
+	for _, f := range factors {
+		base := fromPolynomial(f.base)
+		if f.multiplicity > 1 {
+			base = hold(Pow(base, Num(float64(f.multiplicity))))
+		}
+		result = append(result, base)
+	}
+
+	product := result[0]
+	for _, f := range result[1:] {
+		product = hold(Mul(product, f))
+	}
+	return product
+}
+
+func rationalValue(r *big.Rat) value {
+	numerator, _ := new(big.Float).SetInt(r.Num()).Float64()
+	if r.IsInt() {
+		return Num(numerator)
+	}
+	denominator, _ := new(big.Float).SetInt(r.Denom()).Float64()
+	return Div(Num(numerator), Num(denominator))
+}
+
+func hold(v value) value {
+	v.held = true
+	return v
+}
+
+func expands(pm PatternMatcher) bool {
+	switch pm.(type) {
+	case *distributiveMatcher, *binomial1Matcher, *binomial3Matcher:
+		return true
+	}
+	return false
+}
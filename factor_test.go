@@ -0,0 +1,140 @@
+package equations
+
+import (
+	"testing"
+)
+
+func TestFactor_commonFactor(t *testing.T) {
+	expression := Add(Var(6, "x", 3), Mul(Var(9, "x", 2), Var(1, "y", 1)))
+
+	factored, err := Factor(expression)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if factored.String() != "(3.000000x^2 * (2.000000x + 3.000000y))" {
+		t.Fatalf("expected %v to be (3.000000x^2 * (2.000000x + 3.000000y))", factored)
+	}
+}
+
+func TestFactor_rationalRoots(t *testing.T) {
+	expression := Add(Add(Var(2, "x", 2), Var(-1, "x", 1)), Num(-1))
+
+	factored, err := Factor(expression)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if factored.String() != "((1.000000x + -1.000000) * (2.000000x + 1.000000))" {
+		t.Fatalf("expected %v to be ((1.000000x + -1.000000) * (2.000000x + 1.000000))", factored)
+	}
+}
+
+func TestFactor_squareFree(t *testing.T) {
+	expression := Sub(Add(Var(1, "x", 3), Var(1, "x", 1)), Add(Var(1, "x", 2), Num(1)))
+	expression = Mul(expression, Add(Var(1, "x", 1), Num(-1)))
+
+	factored, err := Factor(expression)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if factored.String() != "((1.000000x^2 + 1.000000) * ((1.000000x + -1.000000) ^ 2.000000))" {
+		t.Fatalf("expected %v to be ((1.000000x^2 + 1.000000) * ((1.000000x + -1.000000) ^ 2.000000))", factored)
+	}
+}
+
+func TestFactor_differenceOfSquares(t *testing.T) {
+	expression := Sub(Var(4, "x", 2), Var(9, "y", 2))
+
+	factored, err := Factor(expression)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if factored.String() != "((2.000000x + 3.000000y) * (2.000000x + -3.000000y))" {
+		t.Fatalf("expected %v to be ((2.000000x + 3.000000y) * (2.000000x + -3.000000y))", factored)
+	}
+	if factored.execute().String() != factored.String() {
+		t.Fatalf("expected %v not to be expanded again", factored.execute())
+	}
+}
+
+func TestFactor_perfectSquare(t *testing.T) {
+	expression := Add(Add(Var(1, "x", 2), Mul(Var(2, "x", 1), Var(1, "y", 1))), Var(1, "y", 2))
+
+	factored, err := Factor(expression)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if factored.String() != "((1.000000x + 1.000000y) ^ 2.000000)" {
+		t.Fatalf("expected %v to be ((1.000000x + 1.000000y) ^ 2.000000)", factored)
+	}
+	if factored.execute().String() != factored.String() {
+		t.Fatalf("expected %v not to be expanded again", factored.execute())
+	}
+}
+
+func TestFactor_notAPolynomial(t *testing.T) {
+	_, err := Factor(Sin(Var(1, "x", 1)))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestFactor_rationalCoefficients(t *testing.T) {
+	expression := Sub(Var(0.5, "x", 2), Num(0.125))
+
+	factored, err := Factor(expression)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if factored.String() != "(((1.000000 / 8.000000) * (2.000000x + -1.000000)) * (2.000000x + 1.000000))" {
+		t.Fatalf("expected %v to be (((1.000000 / 8.000000) * (2.000000x + -1.000000)) * (2.000000x + 1.000000))", factored)
+	}
+}
+
+func TestFactor_substitutedFactorsFold(t *testing.T) {
+	factored, err := Factor(Sub(Var(1, "x", 2), Num(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	eq := NewEquation(factored, Num(0))
+
+	if result := Set(&eq, "x", Num(1)); !result.IsTrue() {
+		t.Fatalf("expected %v to be true", result.left.execute())
+	}
+	if result := Set(&eq, "x", Num(2)); result.left.execute().String() != "3.000000" {
+		t.Fatalf("expected %v to be 3.000000", result.left.execute())
+	}
+	if folded := factored.execute(); folded.String() != factored.String() {
+		t.Fatalf("expected %v not to be expanded again", folded)
+	}
+}
+
+func TestFactor_multivariateSquareFree(t *testing.T) {
+	x, y := Var(1, "x", 1), Var(1, "y", 1)
+	cube := Add(Add(Add(Var(1, "x", 3), Mul(Var(3, "x", 2), y)), Mul(Var(3, "x", 1), Var(1, "y", 2))), Var(1, "y", 3))
+
+	factored, err := Factor(cube)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if factored.String() != "((1.000000x + 1.000000y) ^ 3.000000)" {
+		t.Fatalf("expected %v to be ((1.000000x + 1.000000y) ^ 3.000000)", factored)
+	}
+
+	factored, err = Factor(Add(Add(Add(Mul(x, y), x), y), Num(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if factored.String() != "((1.000000y + 1.000000) * (1.000000x + 1.000000))" {
+		t.Fatalf("expected %v to be ((1.000000y + 1.000000) * (1.000000x + 1.000000))", factored)
+	}
+}
+
+func TestFactor_rationalContent(t *testing.T) {
+	factored, err := Factor(Add(Var(1.0/3, "x", 1), Var(1.0/3, "y", 1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if factored.String() != "((1.000000 / 3.000000) * (1.000000x + 1.000000y))" {
+		t.Fatalf("expected %v to be ((1.000000 / 3.000000) * (1.000000x + 1.000000y))", factored)
+	}
+}
@@ -0,0 +1,74 @@
+package equations
+
+import (
+	"errors"
+	"math"
+)
+
+var functions = map[string]func(float64) float64{
+	"sin": math.Sin,
+	"cos": math.Cos,
+	"exp": math.Exp,
+	"ln":  math.Log,
+	"abs": math.Abs,
+}
+
+var inverseFunctions = map[string]string{
+	"exp": "ln",
+	"ln":  "exp",
+}
+
+func function(name string, arg value) value {
+	return value{left: &arg, op: name}
+}
+
+func Sin(arg value) value {
+	return function("sin", arg)
+}
+
+func Cos(arg value) value {
+	return function("cos", arg)
+}
+
+func Exp(arg value) value {
+	return function("exp", arg)
+}
+
+func Ln(arg value) value {
+	return function("ln", arg)
+}
+
+func Abs(arg value) value {
+	return function("abs", arg)
+}
+
+type isolationError struct {
+	name, op string
+}
+
+func (ie *isolationError) Error() string {
+	return "variable " + ie.name + " cannot be isolated from " + ie.op
+}
+
+func isIsolationError(err error) bool {
+	_, blocked := err.(*isolationError)
+	return blocked
+}
+
+func findValueInFunction(val *value, name string) (*value, path, path, error) {
+	inverse, invertible := inverseFunctions[val.op]
+	if !invertible {
+		if containsVariable(*val.left, name) {
+			return nil, nil, nil, &isolationError{name, val.op}
+		}
+		return nil, nil, nil, errors.New("variable " + name + " not found")
+	}
+
+	found, pathToValue, complementaryPath, err := findValue(val.left, name)
+	if err != nil {
+		return nil, nil, nil, err
+	}
+	pathToValue = append(pathToValue, &opValuePair{inverse, value{}, false})
+	complementaryPath = append(complementaryPath, &opValuePair{inverse, value{}, false})
+	return found, pathToValue, complementaryPath, nil
+}
@@ -0,0 +1,580 @@
+package equations
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+)
+
+func Integrate(expr value, varName string) (value, error) {
+	result, err := integrate(expr, varName)
+	if err != nil {
+		return value{}, err
+	}
+	return result.execute(), nil
+}
+
+func integrate(val value, varName string) (value, error) {
+	if !containsVariable(val, varName) {
+		return Mul(val, Var(1, varName, 1)), nil
+	}
+	if _, unary := functions[val.op]; unary {
+		return integrateFunction(val, varName)
+	}
+
+	switch val.op {
+	case "var":
+		if val.exponent == -1 {
+			return Mul(Num(val.number), Ln(Abs(Var(1, varName, 1)))), nil
+		}
+		return Var(val.number/(val.exponent+1), varName, val.exponent+1), nil
+	case "+", "-":
+		l, err := integrate(*val.left, varName)
+		if err != nil {
+			return value{}, err
+		}
+		r, err := integrate(*val.right, varName)
+		if err != nil {
+			return value{}, err
+		}
+		if val.op == "+" {
+			return Add(l, r), nil
+		}
+		return Sub(l, r), nil
+	case "*":
+		if !containsVariable(*val.left, varName) {
+			r, err := integrate(*val.right, varName)
+			return Mul(*val.left, r), err
+		}
+		if !containsVariable(*val.right, varName) {
+			l, err := integrate(*val.left, varName)
+			return Mul(l, *val.right), err
+		}
+		return integrateProduct(val, varName)
+	case "/":
+		if !containsVariable(*val.right, varName) {
+			l, err := integrate(*val.left, varName)
+			return Div(l, *val.right), err
+		}
+		return integrateQuotient(val, varName)
+	case "^":
+		return integratePower(val, varName)
+	}
+	return value{}, cannotIntegrate(val)
+}
+
+func cannotIntegrate(val value) error {
+	return errors.New("cannot integrate " + val.String())
+}
+
+func linearFactor(u value, varName string) (value, bool) {
+	if !IsLinear(u, varName) {
+		return value{}, false
+	}
+	a, err := Coefficient(u, varName, 1)
+	if err != nil {
+		return value{}, false
+	}
+	a = a.execute()
+	return a, a.op == "num" && a.number != 0
+}
+
+func antiderivative(name string, u value) value {
+	switch name {
+	default:
+		panic("no antiderivative for " + name)
+	case "sin":
+		return Mul(Num(-1), Cos(u))
+	case "cos":
+		return Sin(u)
+	case "exp":
+		return Exp(u)
+	case "ln":
+		return Sub(Mul(u, Ln(u)), u)
+	case "abs":
+		return Div(Mul(u, Abs(u)), Num(2))
+	}
+}
+
+func powerAntiderivative(u value, n float64) value {
+	if n == -1 {
+		return Ln(Abs(u))
+	}
+	return Div(Pow(u, Num(n+1)), Num(n+1))
+}
+
+func integrateFunction(val value, varName string) (value, error) {
+	if a, linear := linearFactor(*val.left, varName); linear {
+		return Div(antiderivative(val.op, *val.left), a), nil
+	}
+	return value{}, cannotIntegrate(val)
+}
+
+func integratePower(val value, varName string) (value, error) {
+	base, exponent := *val.left, val.right.execute()
+	switch {
+	case !containsVariable(exponent, varName) && exponent.op == "num":
+		if a, linear := linearFactor(base, varName); linear {
+			return Div(powerAntiderivative(base, exponent.number), a), nil
+		}
+		if IsPolynomial(val, varName) {
+			return integrate(Expand(val), varName)
+		}
+	case !containsVariable(base, varName):
+		if a, linear := linearFactor(exponent, varName); linear {
+			return Div(val, Mul(a, Ln(base))), nil
+		}
+	}
+	return value{}, cannotIntegrate(val)
+}
+
+func integrateProduct(val value, varName string) (value, error) {
+	if IsPolynomial(val, varName) {
+		return integrate(Expand(val), varName)
+	}
+	if result, substituted := integrateBySubstitution(*val.left, *val.right, varName); substituted {
+		return result, nil
+	}
+	if result, substituted := integrateBySubstitution(*val.right, *val.left, varName); substituted {
+		return result, nil
+	}
+	if terms := expandTerms(val); len(terms) > 1 {
+		return integrate(sum(terms), varName)
+	}
+	return value{}, cannotIntegrate(val)
+}
+
+func constantRatio(a, b value, varName string) (value, bool) {
+	if EqualCommutative(a.execute(), b.execute()) {
+		return Num(1), true
+	}
+	ratio := Div(a, b)
+	if simplified, _, err := SimplifyRational(ratio); err == nil {
+		ratio = simplified
+	} else {
+		ratio = ratio.execute()
+	}
+	return ratio, !containsVariable(ratio, varName)
+}
+
+func integrateBySubstitution(factor, inner value, varName string) (value, bool) {
+	var u, integral value
+	if _, unary := functions[inner.op]; unary {
+		u = *inner.left
+		integral = antiderivative(inner.op, u)
+	} else if exponent := inner.right; inner.op == "^" && !containsVariable(*exponent, varName) && exponent.execute().op == "num" {
+		u = *inner.left
+		integral = powerAntiderivative(u, exponent.execute().number)
+	} else {
+		return value{}, false
+	}
+
+	du, err := Derive(u, varName)
+	if err != nil {
+		return value{}, false
+	}
+	ratio, constant := constantRatio(factor, du, varName)
+	if !constant {
+		return value{}, false
+	}
+	return Mul(ratio, integral), true
+}
+
+func integrateQuotient(val value, varName string) (value, error) {
+	numerator, denominator := *val.left, *val.right
+	du, err := Derive(denominator, varName)
+	if err != nil {
+		return value{}, err
+	}
+	if ratio, constant := constantRatio(numerator, du, varName); constant {
+		return Mul(ratio, Ln(Abs(denominator))), nil
+	}
+
+	if !containsVariable(numerator, varName) {
+		reciprocal := Pow(denominator, Num(-1))
+		if exponent := denominator.right; denominator.op == "^" && !containsVariable(*exponent, varName) {
+			reciprocal = Pow(*denominator.left, Mul(Num(-1), *exponent).execute())
+		}
+		if result, err := integratePower(reciprocal, varName); err == nil {
+			return Mul(numerator, result), nil
+		}
+	}
+
+	if apart, err := Apart(val, varName); err == nil && (apart.op == "+" || apart.op == "-") {
+		return integrate(apart, varName)
+	}
+	return value{}, cannotIntegrate(val)
+}
+
+type QuadratureMethod int
+
+const (
+	AdaptiveSimpson QuadratureMethod = iota
+	GaussKronrod
+)
+
+type QuadratureOptions struct {
+	Method         QuadratureMethod
+	Tolerance      float64
+	MaxDepth       int
+	MaxEvaluations int
+}
+
+func (opts QuadratureOptions) withDefaults() QuadratureOptions {
+	if opts.Tolerance <= 0 {
+		opts.Tolerance = 1e-10
+	}
+	if opts.MaxDepth <= 0 {
+		opts.MaxDepth = 50
+	}
+	if opts.MaxEvaluations <= 0 {
+		opts.MaxEvaluations = 1000000
+	}
+	return opts
+}
+
+func IntegrateDefinite(expr value, varName string, a, b float64, opts QuadratureOptions) (float64, error) {
+	switch {
+	case a == b:
+		return 0, nil
+	case a > b:
+		result, err := IntegrateDefinite(expr, varName, b, a, opts)
+		return -result, err
+	}
+
+	points, err := singularities(expr, varName, a, b)
+	if err != nil {
+		return 0, err
+	}
+	if len(points) > 0 {
+		return integrateAcross(expr, varName, a, b, points, opts)
+	}
+
+	f := residualFunction(expr, varName)
+	if integral, err := Integrate(expr, varName); err == nil && finiteOn(f, a, b) {
+		F := residualFunction(integral, varName)
+		upper, errUpper := F(b)
+		lower, errLower := F(a)
+		if errUpper == nil && errLower == nil && isFinite(upper-lower) {
+			return upper - lower, nil
+		}
+	}
+	return IntegrateNumeric(expr, varName, a, b, opts)
+}
+
+func finiteOn(f realFunction, a, b float64) bool {
+	const samples = 16
+	for i := 0; i <= samples; i++ {
+		y, err := f(a + (b-a)*float64(i)/samples)
+		if err != nil || !isFinite(y) {
+			return false
+		}
+	}
+	return true
+}
+
+func singularities(expr value, varName string, a, b float64) ([]float64, error) {
+	result := &NumericResult{Roots: make([]float64, 0)}
+	var walk func(val value) error
+	walk = func(val value) error {
+		var g *value
+		switch {
+		case val.op == "var" && val.name == varName && val.exponent < 0:
+			x := Var(1, varName, 1)
+			g = &x
+		case val.op == "/" || val.op == "ln":
+			g = val.right
+			if val.op == "ln" {
+				g = val.left
+			}
+		case val.op == "^" && !containsVariable(*val.right, varName):
+			if exponent := val.right.execute(); exponent.op == "num" && exponent.number < 0 {
+				g = val.left
+			}
+		}
+		if g != nil && containsVariable(*g, varName) {
+			zeros, err := zerosOn(*g, varName, a, b)
+			if err != nil {
+				return err
+			}
+			for _, zero := range zeros {
+				result.addRoot(zero, 0, 1e-12)
+			}
+		}
+		for _, child := range []*value{val.left, val.right} {
+			if child != nil {
+				if err := walk(*child); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+	if err := walk(expr); err != nil {
+		return nil, err
+	}
+	sort.Float64s(result.Roots)
+	return result.Roots, nil
+}
+
+func zerosOn(g value, varName string, a, b float64) ([]float64, error) {
+	zeros := make([]float64, 0)
+	if p, err := toPolynomial(g); err == nil && len(p.variables()) == 1 {
+		u := p.univariate(varName)
+		for _, root := range rationalRoots(u) {
+			if x, _ := root.Float64(); a <= x && x <= b {
+				zeros = append(zeros, x)
+			}
+			for u.eval(root).Sign() == 0 {
+				u, _ = u.divmod(upoly{new(big.Rat).Neg(root), big.NewRat(1, 1)})
+			}
+		}
+		if u.isConstant() {
+			return zeros, nil
+		}
+		u, _ = u.divmod(ugcd(u, u.derivative()))
+		g = fromPolynomial(fromUnivariate(u, varName))
+	}
+	result, err := scan(residualFunction(g, varName), NumericOptions{Lower: a, Upper: b, Steps: 256}.withDefaults())
+	if err != nil {
+		return nil, err
+	}
+	return append(zeros, result.Roots...), nil
+}
+
+func integrateAcross(expr value, varName string, a, b float64, points []float64, opts QuadratureOptions) (float64, error) {
+	integral, err := Integrate(expr, varName)
+	if err != nil {
+		return integrateRemovable(expr, varName, a, b, points, opts)
+	}
+	F := residualFunction(integral, varName)
+	for _, c := range points {
+		for _, side := range []float64{-1, 1} {
+			if (c == a && side < 0) || (c == b && side > 0) {
+				continue
+			}
+			if divergesAt(F, c, side) {
+				return 0, fmt.Errorf("the integral of %v diverges at %v", expr, c)
+			}
+		}
+	}
+
+	bounds := append(append([]float64{a}, points...), b)
+	total := 0.0
+	for i := 1; i < len(bounds); i++ {
+		if bounds[i-1] == bounds[i] {
+			continue
+		}
+		upper, err := boundaryValue(F, integral, varName, bounds[i], FromBelow)
+		if err != nil {
+			return 0, err
+		}
+		lower, err := boundaryValue(F, integral, varName, bounds[i-1], FromAbove)
+		if err != nil {
+			return 0, err
+		}
+		total += upper - lower
+	}
+	return total, nil
+}
+
+func integrateRemovable(expr value, varName string, a, b float64, points []float64, opts QuadratureOptions) (float64, error) {
+	limits := make(map[float64]float64, len(points))
+	for _, c := range points {
+		direction := BothSides
+		if c == a {
+			direction = FromAbove
+		} else if c == b {
+			direction = FromBelow
+		}
+		l, err := Limit(expr, varName, c, direction)
+		if err != nil || l.Kind != FiniteLimit {
+			return 0, fmt.Errorf("cannot integrate %v across its singularity at %v", expr, c)
+		}
+		limits[c] = l.Value.number
+	}
+
+	f := residualFunction(expr, varName)
+	return quadrature(func(x float64) (float64, error) {
+		if l, removable := limits[x]; removable {
+			return l, nil
+		}
+		return f(x)
+	}, a, b, opts.withDefaults())
+}
+
+func divergesAt(F realFunction, c, side float64) bool {
+	scale := math.Max(1, math.Abs(c))
+	values := make([]float64, 0, 3)
+	for _, h := range []float64{1e-4, 1e-8, 1e-12} {
+		y, err := F(c + side*h*scale)
+		if err != nil || !isFinite(y) {
+			return true
+		}
+		values = append(values, y)
+	}
+	return math.Abs(values[2]-values[1]) > math.Abs(values[1]-values[0])/2
+}
+
+func boundaryValue(F realFunction, integral value, varName string, x float64, direction Direction) (float64, error) {
+	if y, err := F(x); err == nil && isFinite(y) {
+		return y, nil
+	}
+	l, err := Limit(integral, varName, x, direction)
+	if err != nil {
+		return 0, err
+	}
+	if l.Kind != FiniteLimit {
+		return 0, fmt.Errorf("the antiderivative %v has no finite limit at %v", integral, x)
+	}
+	return l.Value.number, nil
+}
+
+func IntegrateNumeric(expr value, varName string, a, b float64, opts QuadratureOptions) (float64, error) {
+	return quadrature(residualFunction(expr, varName), a, b, opts.withDefaults())
+}
+
+func quadrature(f realFunction, a, b float64, opts QuadratureOptions) (float64, error) {
+	f = limited(f, opts.MaxEvaluations)
+
+	switch opts.Method {
+	default:
+		return 0, fmt.Errorf("unknown quadrature method %d", opts.Method)
+	case AdaptiveSimpson:
+		fa, err := f(a)
+		if err != nil {
+			return 0, err
+		}
+		fb, err := f(b)
+		if err != nil {
+			return 0, err
+		}
+		m := (a + b) / 2
+		fm, err := f(m)
+		if err != nil {
+			return 0, err
+		}
+		whole := (b - a) / 6 * (fa + 4*fm + fb)
+		return simpson(f, a, b, fa, fm, fb, whole, opts.Tolerance, opts.MaxDepth)
+	case GaussKronrod:
+		return gaussKronrod(f, a, b, opts.Tolerance, opts.MaxDepth)
+	}
+}
+
+func limited(f realFunction, maxEvaluations int) realFunction {
+	evaluations := 0
+	return func(x float64) (float64, error) {
+		if evaluations == maxEvaluations {
+			return 0, fmt.Errorf("quadrature exceeded %d evaluations", maxEvaluations)
+		}
+		evaluations++
+		return f(x)
+	}
+}
+
+func simpson(f realFunction, a, b, fa, fm, fb, whole, tolerance float64, depth int) (float64, error) {
+	m := (a + b) / 2
+	lm, rm := (a+m)/2, (m+b)/2
+	flm, err := f(lm)
+	if err != nil {
+		return 0, err
+	}
+	frm, err := f(rm)
+	if err != nil {
+		return 0, err
+	}
+	left := (m - a) / 6 * (fa + 4*flm + fm)
+	right := (b - m) / 6 * (fm + 4*frm + fb)
+	delta := left + right - whole
+
+	switch {
+	case !isFinite(delta):
+		return 0, fmt.Errorf("integrand is not finite on [%v, %v]", a, b)
+	case math.Abs(delta) <= 15*tolerance:
+		return left + right + delta/15, nil
+	case depth <= 0:
+		return 0, fmt.Errorf("adaptive Simpson did not converge on [%v, %v]", a, b)
+	}
+
+	l, err := simpson(f, a, m, fa, flm, fm, left, tolerance/2, depth-1)
+	if err != nil {
+		return 0, err
+	}
+	r, err := simpson(f, m, b, fm, frm, fb, right, tolerance/2, depth-1)
+	if err != nil {
+		return 0, err
+	}
+	return l + r, nil
+}
+
+var kronrodNodes = [8]float64{
+	0.991455371120812639206854697526329,
+	0.949107912342758524526189684047851,
+	0.864864423359769072789712788640926,
+	0.741531185599394439863864773280788,
+	0.586087235467691130294144845693013,
+	0.405845151377397166906606412076961,
+	0.207784955007898467600689403773245,
+	0,
+}
+
+var kronrodWeights = [8]float64{
+	0.022935322010529224963732008058970,
+	0.063092092629978553290700663189204,
+	0.104790010322250183839876322541518,
+	0.140653259715525918745189590510238,
+	0.169004726639267902826583426598550,
+	0.190350578064785409913256402421014,
+	0.204432940075298892414161999234649,
+	0.209482141084727828012999174891714,
+}
+
+var gaussWeights = [4]float64{
+	0.129484966168869693270611432679082,
+	0.279705391489276667901467771423780,
+	0.381830050505118944950369775488975,
+	0.417959183673469387755102040816327,
+}
+
+func gaussKronrod(f realFunction, a, b, tolerance float64, depth int) (float64, error) {
+	center, half := (a+b)/2, (b-a)/2
+	kronrod, gauss := 0.0, 0.0
+	for i, node := range kronrodNodes {
+		points := []float64{center - half*node, center + half*node}
+		if node == 0 {
+			points = points[:1]
+		}
+		for _, x := range points {
+			y, err := f(x)
+			if err != nil {
+				return 0, err
+			}
+			kronrod += kronrodWeights[i] * y
+			if i%2 == 1 {
+				gauss += gaussWeights[i/2] * y
+			}
+		}
+	}
+	kronrod, gauss = kronrod*half, gauss*half
+
+	switch {
+	case !isFinite(kronrod):
+		return 0, fmt.Errorf("integrand is not finite on [%v, %v]", a, b)
+	case math.Abs(kronrod-gauss) <= tolerance:
+		return kronrod, nil
+	case depth <= 0:
+		return 0, fmt.Errorf("Gauss-Kronrod did not converge on [%v, %v]", a, b)
+	}
+
+	l, err := gaussKronrod(f, a, center, tolerance/2, depth-1)
+	if err != nil {
+		return 0, err
+	}
+	r, err := gaussKronrod(f, center, b, tolerance/2, depth-1)
+	if err != nil {
+		return 0, err
+	}
+	return l + r, nil
+}
@@ -0,0 +1,143 @@
+package equations
+
+import (
+	"math"
+	"testing"
+)
+
+func TestIntegrate(t *testing.T) {
+	x := Var(1, "x", 1)
+	tests := []value{
+		Add(Var(3, "x", 2), Num(2)),
+		Var(4, "x", -1),
+		Var(1, "x", -3),
+		Mul(Add(x, Num(1)), Sub(x, Num(2))),
+		Sin(Mul(Num(2), x)),
+		Cos(Add(x, Num(1))),
+		Exp(Var(3, "x", 1)),
+		Ln(x),
+		Pow(Add(Var(2, "x", 1), Num(1)), Num(3)),
+		Pow(Num(2), x),
+		Mul(Var(2, "x", 1), Exp(Var(1, "x", 2))),
+		Mul(Cos(x), Pow(Sin(x), Num(2))),
+		Div(Var(2, "x", 1), Add(Var(1, "x", 2), Num(1))),
+		Div(Num(3), Pow(Add(x, Num(1)), Num(2))),
+		Div(Num(1), Sub(Var(1, "x", 2), Num(1))),
+		Div(Add(Var(1, "x", 2), Num(1)), Var(1, "x", 1)),
+	}
+
+	for _, expr := range tests {
+		integral, err := Integrate(expr, "x")
+		if err != nil {
+			t.Fatal(err)
+		}
+		derivative, _ := Derive(integral, "x")
+		for _, point := range []float64{1.5, 2.25, 3.5} {
+			expected, _ := Evaluate(expr, map[string]float64{"x": point})
+			actual, err := Evaluate(derivative, map[string]float64{"x": point})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if math.Abs(actual-expected) > 1e-9*math.Max(1, math.Abs(expected)) {
+				t.Fatalf("expected d/dx %v to be %v at %v, got %v", integral, expected, point, actual)
+			}
+		}
+	}
+}
+
+func TestIntegrate_logarithm(t *testing.T) {
+	integral, _ := Integrate(Var(1, "x", -1), "x")
+	if integral.String() != "ln(abs(1.000000x))" {
+		t.Fatalf("expected %v to be ln(abs(1.000000x))", integral)
+	}
+}
+
+func TestIntegrate_unsupported(t *testing.T) {
+	if _, err := Integrate(Exp(Var(1, "x", 2)), "x"); err == nil {
+		t.Fatal("expected exp(x^2) not to have an elementary antiderivative here")
+	}
+}
+
+func TestIntegrateDefinite(t *testing.T) {
+	result, err := IntegrateDefinite(Var(3, "x", 2), "x", 0, 2, QuadratureOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(result-8) > 1e-12 {
+		t.Fatalf("expected %v to be 8", result)
+	}
+
+	fallback, err := IntegrateDefinite(Exp(Mul(Num(-1), Var(1, "x", 2))), "x", -3, 3, QuadratureOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if expected := math.Sqrt(math.Pi) * math.Erf(3); math.Abs(fallback-expected) > 1e-9 {
+		t.Fatalf("expected %v to be %v", fallback, expected)
+	}
+}
+
+func TestIntegrateDefinite_singularities(t *testing.T) {
+	if _, err := IntegrateDefinite(Div(Num(1), Var(1, "x", 1)), "x", -1, 2, QuadratureOptions{}); err == nil {
+		t.Fatal("expected the integral of 1/x over [-1, 2] to diverge")
+	}
+	if _, err := IntegrateDefinite(Var(1, "x", -2), "x", -1, 1, QuadratureOptions{}); err == nil {
+		t.Fatal("expected the integral of x^-2 over [-1, 1] to diverge")
+	}
+	if _, err := IntegrateDefinite(Div(Num(1), Pow(Sub(Var(1, "x", 1), Num(1)), Num(2))), "x", 0, 3, QuadratureOptions{}); err == nil {
+		t.Fatal("expected the integral of 1/(x-1)^2 over [0, 3] to diverge")
+	}
+	if _, err := IntegrateDefinite(Div(Var(1, "x", 1), Sub(Var(1, "x", 2), Num(2))), "x", 0, 2, QuadratureOptions{}); err == nil {
+		t.Fatal("expected the integral of x/(x^2-2) over [0, 2] to diverge")
+	}
+
+	result, err := IntegrateDefinite(Pow(Var(1, "x", 1), Num(-0.5)), "x", 0, 4, QuadratureOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(result-4) > 1e-12 {
+		t.Fatalf("expected %v to be 4", result)
+	}
+
+	result, err = IntegrateDefinite(Div(Num(1), Var(1, "x", 1)), "x", 2, 1, QuadratureOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(result+math.Ln2) > 1e-12 {
+		t.Fatalf("expected %v to be -ln 2", result)
+	}
+}
+
+func TestIntegrateDefinite_removableSingularity(t *testing.T) {
+	x := Var(1, "x", 1)
+
+	result, err := IntegrateDefinite(Div(Sin(x), x), "x", 0, 1, QuadratureOptions{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(result-0.946083070367183) > 1e-9 {
+		t.Fatalf("expected %v to be 0.946083070367183", result)
+	}
+
+	if _, err := IntegrateDefinite(Div(Cos(x), x), "x", 0, 1, QuadratureOptions{}); err == nil {
+		t.Fatal("expected cos(x)/x over [0, 1] to fail")
+	}
+}
+
+func TestIntegrateNumeric(t *testing.T) {
+	for _, method := range []QuadratureMethod{AdaptiveSimpson, GaussKronrod} {
+		result, err := IntegrateNumeric(Sin(Var(1, "x", 1)), "x", 0, math.Pi, QuadratureOptions{Method: method})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if math.Abs(result-2) > 1e-9 {
+			t.Fatalf("expected %v to be 2 with method %d", result, method)
+		}
+	}
+
+	if _, err := IntegrateNumeric(Var(1, "x", -1), "x", -1, 1, QuadratureOptions{Method: GaussKronrod, MaxDepth: 10}); err == nil {
+		t.Fatal("expected 1/x over [-1, 1] to fail")
+	}
+	if _, err := IntegrateNumeric(Exp(Var(1, "x", 1)), "x", 0, 1, QuadratureOptions{Tolerance: 1e-15, MaxEvaluations: 100}); err == nil {
+		t.Fatal("expected the evaluation budget to be exhausted")
+	}
+}
@@ -0,0 +1,258 @@
+package equations
+
+import (
+	"errors"
+	"fmt"
+	"math"
+)
+
+type Interval struct {
+	Lower, Upper float64
+}
+
+func NewInterval(lower, upper float64) Interval {
+	if lower > upper {
+		lower, upper = upper, lower
+	}
+	return Interval{lower, upper}
+}
+
+func Point(x float64) Interval {
+	return Interval{x, x}
+}
+
+func (i Interval) String() string {
+	return fmt.Sprintf("[%f, %f]", i.Lower, i.Upper)
+}
+
+func (i Interval) Contains(x float64) bool {
+	return i.Lower <= x && x <= i.Upper
+}
+
+func (i Interval) Width() float64 {
+	return i.Upper - i.Lower
+}
+
+func (i Interval) isPoint() bool {
+	return i.Lower == i.Upper
+}
+
+func (i Interval) outward() Interval {
+	return Interval{math.Nextafter(i.Lower, math.Inf(-1)), math.Nextafter(i.Upper, math.Inf(1))}
+}
+
+func (i Interval) add(other Interval) Interval {
+	return Interval{i.Lower + other.Lower, i.Upper + other.Upper}.outward()
+}
+
+func (i Interval) sub(other Interval) Interval {
+	return Interval{i.Lower - other.Upper, i.Upper - other.Lower}.outward()
+}
+
+func (i Interval) mul(other Interval) Interval {
+	products := []float64{
+		product(i.Lower, other.Lower),
+		product(i.Lower, other.Upper),
+		product(i.Upper, other.Lower),
+		product(i.Upper, other.Upper),
+	}
+	return hull(products).outward()
+}
+
+func product(a, b float64) float64 {
+	if a == 0 || b == 0 {
+		return 0
+	}
+	return a * b
+}
+
+func hull(bounds []float64) Interval {
+	result := Interval{bounds[0], bounds[0]}
+	for _, b := range bounds[1:] {
+		result.Lower = math.Min(result.Lower, b)
+		result.Upper = math.Max(result.Upper, b)
+	}
+	return result
+}
+
+func (i Interval) div(other Interval) (Interval, error) {
+	if other.Lower == 0 && other.Upper == 0 {
+		return Interval{}, errors.New("division by zero")
+	}
+
+	var reciprocal Interval
+	switch {
+	case other.Contains(0) && other.Lower < 0 && other.Upper > 0:
+		return Interval{math.Inf(-1), math.Inf(1)}, nil
+	case other.Lower == 0:
+		reciprocal = Interval{1 / other.Upper, math.Inf(1)}
+	case other.Upper == 0:
+		reciprocal = Interval{math.Inf(-1), 1 / other.Lower}
+	default:
+		reciprocal = Interval{1 / other.Upper, 1 / other.Lower}
+	}
+	if i.Contains(0) && math.IsInf(reciprocal.Width(), 1) {
+		return Interval{math.Inf(-1), math.Inf(1)}, nil
+	}
+	return i.mul(reciprocal.outward()), nil
+}
+
+func (i Interval) pow(exponent Interval) (Interval, error) {
+	if !exponent.isPoint() {
+		if i.Lower <= 0 {
+			return Interval{}, fmt.Errorf("%v ^ %v is undefined for non-positive bases", i, exponent)
+		}
+		return exponent.mul(i.apply(math.Log)).apply(math.Exp), nil
+	}
+
+	e := exponent.Lower
+	integer := e == math.Trunc(e)
+	switch {
+	case e == 0:
+		return Point(1), nil
+	case e == 1:
+		return i, nil
+	case integer && e < 0:
+		positive, err := i.pow(Point(-e))
+		if err != nil {
+			return Interval{}, err
+		}
+		return Point(1).div(positive)
+	case integer && math.Mod(e, 2) == 0:
+		switch {
+		case i.Lower >= 0:
+			return Interval{math.Pow(i.Lower, e), math.Pow(i.Upper, e)}.outward(), nil
+		case i.Upper <= 0:
+			return Interval{math.Pow(i.Upper, e), math.Pow(i.Lower, e)}.outward(), nil
+		default:
+			return Interval{0, math.Max(math.Pow(i.Lower, e), math.Pow(i.Upper, e))}.outward(), nil
+		}
+	case integer:
+		return Interval{math.Pow(i.Lower, e), math.Pow(i.Upper, e)}.outward(), nil
+	case i.Lower < 0:
+		return Interval{}, fmt.Errorf("%v ^ %v is undefined for negative bases", i, e)
+	case e > 0:
+		return Interval{math.Pow(i.Lower, e), math.Pow(i.Upper, e)}.outward(), nil
+	default:
+		return Interval{math.Pow(i.Upper, e), math.Pow(i.Lower, e)}.outward(), nil
+	}
+}
+
+func (i Interval) apply(increasing func(float64) float64) Interval {
+	return Interval{increasing(i.Lower), increasing(i.Upper)}.outward()
+}
+
+func (i Interval) function(name string) (Interval, error) {
+	switch name {
+	default:
+		return Interval{}, errors.New("no interval rule for " + name)
+	case "exp":
+		return i.apply(math.Exp), nil
+	case "ln":
+		if i.Upper <= 0 {
+			return Interval{}, fmt.Errorf("ln is undefined for %v", i)
+		}
+		return Interval{math.Log(math.Max(i.Lower, 0)), math.Log(i.Upper)}.outward(), nil
+	case "abs":
+		switch {
+		case i.Lower >= 0:
+			return i, nil
+		case i.Upper <= 0:
+			return Interval{-i.Upper, -i.Lower}, nil
+		default:
+			return Interval{0, math.Max(-i.Lower, i.Upper)}, nil
+		}
+	case "sin":
+		return i.sin(), nil
+	case "cos":
+		return Interval{i.Lower + math.Pi/2, i.Upper + math.Pi/2}.outward().sin(), nil
+	}
+}
+
+func (i Interval) sin() Interval {
+	if i.Width() >= 2*math.Pi || math.IsInf(i.Width(), 0) {
+		return Interval{-1, 1}
+	}
+	result := hull([]float64{math.Sin(i.Lower), math.Sin(i.Upper)})
+	if containsPhase(i, math.Pi/2) {
+		result.Upper = 1
+	}
+	if containsPhase(i, -math.Pi/2) {
+		result.Lower = -1
+	}
+	result = result.outward()
+	return Interval{math.Max(result.Lower, -1), math.Min(result.Upper, 1)}
+}
+
+func containsPhase(i Interval, phase float64) bool {
+	k := math.Ceil((i.Lower - phase) / (2 * math.Pi))
+	return phase+2*math.Pi*k <= i.Upper
+}
+
+func EvaluateInterval(val value, vars map[string]Interval) (Interval, error) {
+	if _, unary := functions[val.op]; unary {
+		arg, err := EvaluateInterval(*val.left, vars)
+		if err != nil {
+			return Interval{}, err
+		}
+		return arg.function(val.op)
+	}
+
+	switch val.op {
+	default:
+		return Interval{}, errors.New("cannot evaluate operator " + val.op)
+	case "num":
+		if val.imaginary != 0 {
+			return Interval{}, errors.New("cannot bound complex number " + val.String())
+		}
+		return Point(val.number), nil
+	case "const":
+		return Point(val.number).outward(), nil
+	case "var":
+		x, present := vars[val.name]
+		if !present {
+			return Interval{}, errors.New("no interval for variable " + val.name)
+		}
+		power, err := x.pow(Point(val.exponent))
+		if err != nil {
+			return Interval{}, err
+		}
+		if val.number == 1 {
+			return power, nil
+		}
+		return Point(val.number).mul(power), nil
+	case "+", "-", "*", "/", "^":
+		l, err := EvaluateInterval(*val.left, vars)
+		if err != nil {
+			return Interval{}, err
+		}
+		r, err := EvaluateInterval(*val.right, vars)
+		if err != nil {
+			return Interval{}, err
+		}
+		switch val.op {
+		case "+":
+			return l.add(r), nil
+		case "-":
+			return l.sub(r), nil
+		case "*":
+			return l.mul(r), nil
+		case "/":
+			return l.div(r)
+		default:
+			return l.pow(r)
+		}
+	}
+}
+
+func BoundSolveTo(eq *equation, varName string, vars map[string]Interval) (*value, Interval, error) {
+	result, err := SolveTo(eq, varName)
+	if err != nil {
+		return nil, Interval{}, err
+	}
+	bound, err := EvaluateInterval(*result, vars)
+	if err != nil {
+		return result, Interval{}, err
+	}
+	return result, bound, nil
+}
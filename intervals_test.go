@@ -0,0 +1,106 @@
+package equations_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gossie/equations"
+)
+
+func assertEncloses(t *testing.T, result equations.Interval, lower, upper float64) {
+	t.Helper()
+	if result.Lower > lower || result.Upper < upper {
+		t.Fatalf("expected %v to enclose [%v, %v]", result, lower, upper)
+	}
+	if result.Lower < lower-1e-9 || result.Upper > upper+1e-9 {
+		t.Fatalf("expected %v to be tight around [%v, %v]", result, lower, upper)
+	}
+}
+
+func TestEvaluateInterval(t *testing.T) {
+	vars := map[string]equations.Interval{"r": equations.NewInterval(3.9, 4.1), "x": equations.NewInterval(-2, 1)}
+
+	area, err := equations.EvaluateInterval(equations.Mul(equations.Num(math.Pi), equations.Var(1, "r", 2)), vars)
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEncloses(t, area, math.Pi*3.9*3.9, math.Pi*4.1*4.1)
+
+	square, _ := equations.EvaluateInterval(equations.Var(1, "x", 2), vars)
+	assertEncloses(t, square, 0, 4)
+
+	cube, _ := equations.EvaluateInterval(equations.Var(1, "x", 3), vars)
+	assertEncloses(t, cube, -8, 1)
+
+	difference, _ := equations.EvaluateInterval(equations.Sub(equations.Var(1, "r", 1), equations.Var(1, "x", 1)), vars)
+	assertEncloses(t, difference, 2.9, 6.1)
+
+	root, _ := equations.EvaluateInterval(equations.Pow(equations.Var(1, "r", 1), equations.Num(0.5)), vars)
+	assertEncloses(t, root, math.Sqrt(3.9), math.Sqrt(4.1))
+}
+
+func TestEvaluateInterval_outwardRounding(t *testing.T) {
+	vars := map[string]equations.Interval{"x": equations.Point(0.1)}
+
+	a, b := 0.1, 0.2
+	result, _ := equations.EvaluateInterval(equations.Add(equations.Var(1, "x", 1), equations.Num(b)), vars)
+	if result.Lower >= a+b || result.Upper <= a+b || !result.Contains(0.3) {
+		t.Fatalf("expected %v to be rounded outwards", result)
+	}
+}
+
+func TestEvaluateInterval_division(t *testing.T) {
+	tests := []struct {
+		divisor         equations.Interval
+		lower, upper    float64
+		expectedFailure bool
+	}{
+		{equations.NewInterval(2, 4), 0.25, 1, false},
+		{equations.NewInterval(0, 1), 1, math.Inf(1), false},
+		{equations.NewInterval(-1, 0), math.Inf(-1), -1, false},
+		{equations.NewInterval(-1, 2), math.Inf(-1), math.Inf(1), false},
+		{equations.Point(0), 0, 0, true},
+	}
+
+	for _, test := range tests {
+		vars := map[string]equations.Interval{"x": equations.NewInterval(1, 2), "y": test.divisor}
+		result, err := equations.EvaluateInterval(equations.Div(equations.Var(1, "x", 1), equations.Var(1, "y", 1)), vars)
+		if test.expectedFailure {
+			if err == nil {
+				t.Fatalf("expected an error for %v", test.divisor)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+		assertEncloses(t, result, test.lower, test.upper)
+	}
+}
+
+func TestEvaluateInterval_functions(t *testing.T) {
+	vars := map[string]equations.Interval{"x": equations.NewInterval(0, math.Pi)}
+
+	sin, _ := equations.EvaluateInterval(equations.Sin(equations.Var(1, "x", 1)), vars)
+	assertEncloses(t, sin, 0, 1)
+
+	cos, _ := equations.EvaluateInterval(equations.Cos(equations.Var(1, "x", 1)), vars)
+	assertEncloses(t, cos, -1, 1)
+
+	exp, _ := equations.EvaluateInterval(equations.Exp(equations.Var(1, "x", 1)), vars)
+	assertEncloses(t, exp, 1, math.Exp(math.Pi))
+
+	if _, err := equations.EvaluateInterval(equations.Ln(equations.Var(-1, "x", 1)), map[string]equations.Interval{"x": equations.NewInterval(1, 2)}); err == nil {
+		t.Fatal("expected ln of a negative interval to fail")
+	}
+}
+
+func TestBoundSolveTo(t *testing.T) {
+	eq := equations.NewEquation(equations.Var(2, "x", 1), equations.Add(equations.Var(1, "y", 1), equations.Num(1)))
+
+	_, bound, err := equations.BoundSolveTo(&eq, "x", map[string]equations.Interval{"y": equations.NewInterval(3, 5)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertEncloses(t, bound, 2, 3)
+}
@@ -0,0 +1,516 @@
+package equations
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+)
+
+type Direction int
+
+const (
+	BothSides Direction = iota
+	FromBelow
+	FromAbove
+)
+
+type LimitKind int
+
+const (
+	FiniteLimit LimitKind = iota
+	PositiveInfinity
+	NegativeInfinity
+	DoesNotExist
+)
+
+type LimitResult struct {
+	Kind  LimitKind
+	Value value
+}
+
+func (r *LimitResult) String() string {
+	switch r.Kind {
+	default:
+		panic(fmt.Sprintf("unknown limit kind %d", r.Kind))
+	case FiniteLimit:
+		return r.Value.String()
+	case PositiveInfinity:
+		return "∞"
+	case NegativeInfinity:
+		return "-∞"
+	case DoesNotExist:
+		return "does not exist"
+	}
+}
+
+const lHopitalSteps = 8
+
+var errIndeterminate = errors.New("indeterminate form")
+
+type limitContext struct {
+	varName string
+	point   float64
+	side    float64
+	steps   int
+}
+
+func Limit(expr value, varName string, point float64, direction Direction) (*LimitResult, error) {
+	if math.IsNaN(point) {
+		return nil, errors.New("the limit point must be a number")
+	}
+	if math.IsInf(point, 0) && ((point > 0 && direction == FromAbove) || (point < 0 && direction == FromBelow)) {
+		return nil, fmt.Errorf("cannot approach %v from that side", point)
+	}
+	for _, name := range Variables(expr) {
+		if name != varName {
+			return parametricLimit(expr, varName, point, name)
+		}
+	}
+	if math.IsInf(point, 0) {
+		return limitFrom(expr, varName, point, -math.Copysign(1, point))
+	}
+
+	switch direction {
+	case FromBelow:
+		return limitFrom(expr, varName, point, -1)
+	case FromAbove:
+		return limitFrom(expr, varName, point, 1)
+	}
+	below, err := limitFrom(expr, varName, point, -1)
+	if err != nil {
+		return nil, err
+	}
+	above, err := limitFrom(expr, varName, point, 1)
+	if err != nil {
+		return nil, err
+	}
+	if below.Kind != above.Kind || (below.Kind == FiniteLimit && !sameLimit(below.Value.number, above.Value.number)) {
+		return &LimitResult{Kind: DoesNotExist}, nil
+	}
+	return below, nil
+}
+
+func sameLimit(a, b float64) bool {
+	return math.Abs(a-b) <= 1e-9*math.Max(1, math.Abs(a))
+}
+
+// parametricLimit handles rational functions whose coefficients depend on
+// other variables; the result holds for generic values of those parameters.
+func parametricLimit(expr value, varName string, point float64, parameter string) (*LimitResult, error) {
+	r, err := toRational(SubstituteConstants(expr))
+	if err != nil {
+		return nil, errors.New("the limit depends on variable " + parameter + ", which is only supported for rational functions")
+	}
+	g := polynomialGCD(r.numerator, r.denominator)
+	numerator, _ := divide(r.numerator, g)
+	denominator, _ := divide(r.denominator, g)
+
+	if math.IsInf(point, 0) {
+		m, n := numerator.degree(varName), denominator.degree(varName)
+		switch {
+		case numerator.isZero() || m < n:
+			return &LimitResult{Kind: FiniteLimit, Value: Num(0)}, nil
+		case m == n:
+			ratio := rationalFunction{numerator.coefficient(varName, m), denominator.coefficient(varName, n)}
+			return &LimitResult{Kind: FiniteLimit, Value: ratio.normalized().value()}, nil
+		}
+		return nil, fmt.Errorf("the sign of the infinite limit of %v depends on %v", expr, parameter)
+	}
+
+	a := ratFromFloat(point)
+	atPoint := rationalFunction{evaluateIn(numerator, varName, a), evaluateIn(denominator, varName, a)}
+	if atPoint.denominator.isZero() {
+		return nil, fmt.Errorf("the sign of the infinite limit of %v depends on %v", expr, parameter)
+	}
+	simplified, _, err := SimplifyRational(atPoint.value())
+	if err != nil {
+		return nil, err
+	}
+	return &LimitResult{Kind: FiniteLimit, Value: simplified}, nil
+}
+
+func evaluateIn(p polynomial, name string, x *big.Rat) polynomial {
+	result := newPolynomial()
+	for k := p.degree(name); k >= 0; k-- {
+		result = result.scale(x).add(p.coefficient(name, k))
+	}
+	return result
+}
+
+func limitFrom(expr value, varName string, point, side float64) (*LimitResult, error) {
+	c := &limitContext{varName: varName, point: point, side: side}
+	l, err := c.limit(expr)
+	if err == errIndeterminate {
+		return nil, fmt.Errorf("cannot determine the limit of %v as %v approaches %v", expr, varName, point)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	switch {
+	case math.IsNaN(l):
+		return &LimitResult{Kind: DoesNotExist}, nil
+	case math.IsInf(l, 1):
+		return &LimitResult{Kind: PositiveInfinity}, nil
+	case math.IsInf(l, -1):
+		return &LimitResult{Kind: NegativeInfinity}, nil
+	}
+	return &LimitResult{Kind: FiniteLimit, Value: Num(l)}, nil
+}
+
+func (c *limitContext) limit(val value) (float64, error) {
+	if l, ok := c.rationalLimit(val); ok {
+		return l, nil
+	}
+
+	if _, unary := functions[val.op]; unary {
+		return c.functionLimit(val)
+	}
+
+	switch val.op {
+	default:
+		return 0, errors.New("cannot take the limit of operator " + val.op)
+	case "num":
+		if val.imaginary != 0 {
+			return 0, errors.New("cannot take the limit of complex number " + val.String())
+		}
+		return val.number, nil
+	case "const":
+		return val.number, nil
+	case "var":
+		if val.name != c.varName {
+			return 0, errors.New("the limit depends on variable " + val.name)
+		}
+		l, err := c.power(Var(1, val.name, 1), c.point, val.exponent)
+		return val.number * l, err
+	case "+", "-":
+		return c.sumLimit(val)
+	case "*":
+		return c.productLimit(*val.left, *val.right)
+	case "/":
+		return c.quotientLimit(*val.left, *val.right)
+	case "^":
+		exponent := val.right.execute()
+		if exponent.op != "num" || exponent.imaginary != 0 {
+			return c.limit(Exp(Mul(*val.right, Ln(*val.left))))
+		}
+		base, err := c.limit(*val.left)
+		if err != nil {
+			return 0, err
+		}
+		return c.power(*val.left, base, exponent.number)
+	}
+}
+
+func (c *limitContext) rationalLimit(val value) (float64, bool) {
+	r, err := toRational(val)
+	if err != nil {
+		return 0, false
+	}
+	for _, p := range []polynomial{r.numerator, r.denominator} {
+		for _, name := range p.variables() {
+			if name != c.varName {
+				return 0, false
+			}
+		}
+	}
+
+	numerator, denominator := r.numerator.univariate(c.varName), r.denominator.univariate(c.varName)
+	if len(numerator) == 0 {
+		return 0, true
+	}
+	if math.IsInf(c.point, 0) {
+		ratio, _ := new(big.Rat).Quo(numerator.lead(), denominator.lead()).Float64()
+		excess := numerator.degree() - denominator.degree()
+		switch {
+		case excess < 0:
+			return 0, true
+		case excess == 0:
+			return ratio, true
+		}
+		return math.Inf(int(sign(ratio) * math.Pow(math.Copysign(1, c.point), float64(excess)))), true
+	}
+
+	a := ratFromFloat(c.point)
+	numerator, m := divideRoot(numerator, a)
+	denominator, n := divideRoot(denominator, a)
+	if m >= n {
+		if m > n {
+			return 0, true
+		}
+		ratio, _ := new(big.Rat).Quo(numerator.eval(a), denominator.eval(a)).Float64()
+		return ratio, true
+	}
+	ratio := new(big.Rat).Quo(numerator.eval(a), denominator.eval(a))
+	return math.Inf(ratio.Sign() * int(math.Pow(c.side, float64(n-m)))), true
+}
+
+func divideRoot(p upoly, a *big.Rat) (upoly, int) {
+	root := upoly{new(big.Rat).Neg(a), big.NewRat(1, 1)}
+	multiplicity := 0
+	for len(p) > 1 && p.eval(a).Sign() == 0 {
+		p, _ = p.divmod(root)
+		multiplicity++
+	}
+	return p, multiplicity
+}
+
+func sign(x float64) float64 {
+	switch {
+	case x > 0:
+		return 1
+	case x < 0:
+		return -1
+	}
+	return 0
+}
+
+func (c *limitContext) functionLimit(val value) (float64, error) {
+	arg, err := c.limit(*val.left)
+	if err != nil || math.IsNaN(arg) {
+		return arg, err
+	}
+
+	switch val.op {
+	case "sin", "cos":
+		if math.IsInf(arg, 0) {
+			return math.NaN(), nil
+		}
+	case "ln":
+		if arg < 0 {
+			return math.NaN(), nil
+		}
+		if arg == 0 {
+			s, err := c.sign(*val.left)
+			if err != nil {
+				return 0, err
+			}
+			if s <= 0 {
+				return math.NaN(), nil
+			}
+			return math.Inf(-1), nil
+		}
+	}
+	return functions[val.op](arg), nil
+}
+
+func (c *limitContext) sumLimit(val value) (float64, error) {
+	l, err := c.limit(*val.left)
+	if err != nil {
+		return 0, err
+	}
+	r, err := c.limit(*val.right)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(l) || math.IsNaN(r) {
+		return math.NaN(), nil
+	}
+	if val.op == "-" {
+		r = -r
+	}
+	if result := l + r; !math.IsNaN(result) {
+		return result, nil
+	}
+
+	other := *val.right
+	if val.op == "-" {
+		other = Mul(Num(-1), other)
+	}
+	return c.productLimit(*val.left, Add(Num(1), Div(other, *val.left)))
+}
+
+func (c *limitContext) productLimit(left, right value) (float64, error) {
+	l, err := c.limit(left)
+	if err != nil {
+		return 0, err
+	}
+	r, err := c.limit(right)
+	if err != nil {
+		return 0, err
+	}
+	if (math.IsNaN(l) && r == 0 && bounded(left)) || (math.IsNaN(r) && l == 0 && bounded(right)) {
+		return 0, nil
+	}
+	if math.IsNaN(l) || math.IsNaN(r) {
+		return math.NaN(), nil
+	}
+	if result := l * r; !math.IsNaN(result) {
+		return result, nil
+	}
+
+	if l != 0 {
+		left, right = right, left
+	}
+	if result, err := c.limit(Div(left, reciprocal(right))); err != errIndeterminate {
+		return result, err
+	}
+	return c.limit(Div(right, reciprocal(left)))
+}
+
+func reciprocal(val value) value {
+	switch {
+	case val.op == "exp":
+		return Exp(Mul(Num(-1), *val.left))
+	case val.op == "^":
+		return Pow(*val.left, Mul(Num(-1), *val.right))
+	case val.op == "/":
+		return Div(*val.right, *val.left)
+	case val.op == "var" && val.number != 0:
+		return Var(1/val.number, val.name, -val.exponent)
+	}
+	return Div(Num(1), val)
+}
+
+func bounded(val value) bool {
+	switch val.op {
+	case "num", "sin", "cos":
+		return true
+	case "+", "-", "*":
+		return bounded(*val.left) && bounded(*val.right)
+	}
+	return false
+}
+
+func (c *limitContext) quotientLimit(numerator, denominator value) (float64, error) {
+	l, err := c.limit(numerator)
+	if err != nil {
+		return 0, err
+	}
+	r, err := c.limit(denominator)
+	if err != nil {
+		return 0, err
+	}
+	if math.IsNaN(l) && math.IsInf(r, 0) && bounded(numerator) {
+		return 0, nil
+	}
+	if math.IsNaN(l) || math.IsNaN(r) {
+		return math.NaN(), nil
+	}
+	if (l == 0 && r == 0) || (math.IsInf(l, 0) && math.IsInf(r, 0)) {
+		return c.lHopital(numerator, denominator)
+	}
+	if r == 0 {
+		s, err := c.sign(denominator)
+		if err != nil {
+			return 0, err
+		}
+		if s == 0 {
+			return math.NaN(), nil
+		}
+		return l / math.Copysign(0, s), nil
+	}
+	return l / r, nil
+}
+
+func (c *limitContext) lHopital(numerator, denominator value) (float64, error) {
+	if c.steps == lHopitalSteps {
+		return 0, errIndeterminate
+	}
+	c.steps++
+	defer func() { c.steps-- }()
+	numerator, err := c.withoutAbs(numerator)
+	if err != nil {
+		return 0, err
+	}
+	denominator, err = c.withoutAbs(denominator)
+	if err != nil {
+		return 0, err
+	}
+	dn, err := Derive(numerator, c.varName)
+	if err != nil {
+		return 0, err
+	}
+	dd, err := Derive(denominator, c.varName)
+	if err != nil {
+		return 0, err
+	}
+	return c.limit(Div(dn, dd))
+}
+
+// withoutAbs replaces abs(u) by ±u where u keeps one sign on the approached
+// side, so that differentiating does not reintroduce the same 0/0 form.
+func (c *limitContext) withoutAbs(val value) (value, error) {
+	if val.op == "abs" {
+		s, err := c.sign(*val.left)
+		if err != nil || s == 0 {
+			return val, err
+		}
+		return Mul(Num(s), *val.left), nil
+	}
+	for _, child := range []**value{&val.left, &val.right} {
+		if *child == nil {
+			continue
+		}
+		rewritten, err := c.withoutAbs(**child)
+		if err != nil {
+			return val, err
+		}
+		*child = &rewritten
+	}
+	return val, nil
+}
+
+func (c *limitContext) power(base value, l, exponent float64) (float64, error) {
+	integral := exponent == math.Trunc(exponent)
+	if l < 0 && !integral {
+		return math.NaN(), nil
+	}
+	if l == 0 && exponent < 0 {
+		s, err := c.sign(base)
+		if err != nil {
+			return 0, err
+		}
+		if s == 0 || (s < 0 && !integral) {
+			return math.NaN(), nil
+		}
+		return math.Pow(math.Copysign(0, s), exponent), nil
+	}
+	return math.Pow(l, exponent), nil
+}
+
+// sign reports the sign of val next to the limit point, 0 when it keeps
+// changing, and errIndeterminate when the probes underflow or fail.
+func (c *limitContext) sign(val value) (float64, error) {
+	if !math.IsInf(c.point, 0) {
+		derivative := val
+		for k := 0; k <= lHopitalSteps; k++ {
+			var d float64
+			var err error
+			if k > 0 {
+				derivative, err = Derive(derivative, c.varName)
+			}
+			if err == nil {
+				d, err = Evaluate(derivative, map[string]float64{c.varName: c.point})
+			}
+			if err != nil || !isFinite(d) {
+				break
+			}
+			if math.Abs(d) > 1e-12 {
+				return sign(d) * math.Pow(c.side, float64(k)), nil
+			}
+		}
+	}
+
+	steps := []float64{1e-4, 1e-6, 1e-8}
+	if math.IsInf(c.point, 0) {
+		steps = []float64{1e4, 1e8, 1e12}
+	}
+	result := 0.0
+	for i, h := range steps {
+		x := c.point + c.side*h
+		if math.IsInf(c.point, 0) {
+			x = -c.side * h
+		}
+		y, err := Evaluate(val, map[string]float64{c.varName: x})
+		if err != nil || math.IsNaN(y) || y == 0 {
+			return 0, errIndeterminate
+		}
+		if i > 0 && sign(y) != result {
+			return 0, nil
+		}
+		result = sign(y)
+	}
+	return result, nil
+}
@@ -0,0 +1,134 @@
+package equations_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gossie/equations"
+)
+
+func assertLimit(t *testing.T, result *equations.LimitResult, err error, expected string) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.String() != expected {
+		t.Fatalf("expected limit %v to be %v", result, expected)
+	}
+}
+
+func TestLimit_removableSingularity(t *testing.T) {
+	x := equations.Var(1, "x", 1)
+	expr := equations.Div(equations.Sub(equations.Var(1, "x", 2), equations.Num(1)), equations.Sub(x, equations.Num(1)))
+
+	result, err := equations.Limit(expr, "x", 1, equations.BothSides)
+	assertLimit(t, result, err, "2.000000")
+}
+
+func TestLimit_lHopital(t *testing.T) {
+	x := equations.Var(1, "x", 1)
+
+	result, err := equations.Limit(equations.Div(equations.Sin(x), x), "x", 0, equations.BothSides)
+	assertLimit(t, result, err, "1.000000")
+
+	result, err = equations.Limit(equations.Div(equations.Sub(equations.Num(1), equations.Cos(x)), equations.Var(1, "x", 2)), "x", 0, equations.BothSides)
+	assertLimit(t, result, err, "0.500000")
+
+	result, err = equations.Limit(equations.Mul(x, equations.Ln(x)), "x", 0, equations.FromAbove)
+	assertLimit(t, result, err, "0.000000")
+}
+
+func TestLimit_oneSided(t *testing.T) {
+	result, err := equations.Limit(equations.Var(1, "x", -1), "x", 0, equations.FromBelow)
+	assertLimit(t, result, err, "-∞")
+
+	result, err = equations.Limit(equations.Var(1, "x", -1), "x", 0, equations.FromAbove)
+	assertLimit(t, result, err, "∞")
+
+	result, err = equations.Limit(equations.Var(1, "x", -1), "x", 0, equations.BothSides)
+	assertLimit(t, result, err, "does not exist")
+
+	result, err = equations.Limit(equations.Var(1, "x", -2), "x", 0, equations.BothSides)
+	assertLimit(t, result, err, "∞")
+}
+
+func TestLimit_atInfinity(t *testing.T) {
+	x := equations.Var(1, "x", 1)
+
+	rational := equations.Div(equations.Add(equations.Var(3, "x", 2), x), equations.Sub(equations.Var(2, "x", 2), equations.Num(5)))
+	result, err := equations.Limit(rational, "x", math.Inf(-1), equations.BothSides)
+	assertLimit(t, result, err, "1.500000")
+
+	result, err = equations.Limit(equations.Div(equations.Exp(x), equations.Var(1, "x", 2)), "x", math.Inf(1), equations.BothSides)
+	assertLimit(t, result, err, "∞")
+
+	result, err = equations.Limit(equations.Sub(x, equations.Ln(x)), "x", math.Inf(1), equations.BothSides)
+	assertLimit(t, result, err, "∞")
+
+	compound := equations.Pow(equations.Add(equations.Num(1), equations.Var(1, "x", -1)), x)
+	result, err = equations.Limit(compound, "x", math.Inf(1), equations.BothSides)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Kind != equations.FiniteLimit || math.Abs(result.Value.Number()-math.E) > 1e-9 {
+		t.Fatalf("expected %v to be e", result)
+	}
+
+	result, err = equations.Limit(equations.Div(equations.Sin(x), x), "x", math.Inf(1), equations.BothSides)
+	assertLimit(t, result, err, "0.000000")
+}
+
+func TestLimit_exponentialDecay(t *testing.T) {
+	x := equations.Var(1, "x", 1)
+	decay := equations.Exp(equations.Mul(equations.Num(-1), x))
+
+	result, err := equations.Limit(equations.Mul(x, decay), "x", math.Inf(1), equations.BothSides)
+	assertLimit(t, result, err, "0.000000")
+
+	result, err = equations.Limit(equations.Mul(equations.Var(1, "x", 2), decay), "x", math.Inf(1), equations.BothSides)
+	assertLimit(t, result, err, "0.000000")
+}
+
+func TestLimit_parameters(t *testing.T) {
+	x, a := equations.Var(1, "x", 1), equations.Var(1, "a", 1)
+
+	result, err := equations.Limit(equations.Div(equations.Mul(a, x), x), "x", 0, equations.BothSides)
+	assertLimit(t, result, err, "1.000000a")
+
+	quotient := equations.Div(equations.Sub(equations.Mul(a, equations.Var(1, "x", 2)), a), equations.Sub(x, equations.Num(1)))
+	result, err = equations.Limit(quotient, "x", 1, equations.BothSides)
+	assertLimit(t, result, err, "2.000000a")
+
+	result, err = equations.Limit(equations.Div(equations.Mul(a, equations.Var(1, "x", 2)), equations.Var(2, "x", 2)), "x", math.Inf(1), equations.BothSides)
+	assertLimit(t, result, err, "0.500000a")
+
+	if _, err := equations.Limit(equations.Div(a, x), "x", 0, equations.BothSides); err == nil {
+		t.Fatal("expected an error for an infinite limit whose sign depends on a")
+	}
+}
+
+func TestLimit_doesNotExist(t *testing.T) {
+	x := equations.Var(1, "x", 1)
+	result, err := equations.Limit(equations.Div(equations.Abs(x), x), "x", 0, equations.BothSides)
+	assertLimit(t, result, err, "does not exist")
+
+	result, err = equations.Limit(equations.Sin(equations.Var(1, "x", -1)), "x", 0, equations.BothSides)
+	assertLimit(t, result, err, "does not exist")
+
+	result, err = equations.Limit(equations.Exp(equations.Var(-1, "x", -1)), "x", 0, equations.BothSides)
+	assertLimit(t, result, err, "does not exist")
+
+	result, err = equations.Limit(equations.Ln(equations.Var(1, "x", 1)), "x", -1, equations.BothSides)
+	assertLimit(t, result, err, "does not exist")
+}
+
+func TestLimit_errors(t *testing.T) {
+	x := equations.Var(1, "x", 1)
+
+	if _, err := equations.Limit(equations.Div(equations.Sin(x), equations.Var(1, "y", 1)), "x", 0, equations.BothSides); err == nil {
+		t.Fatal("expected an error for a free variable outside a rational function")
+	}
+	if _, err := equations.Limit(x, "x", math.Inf(1), equations.FromAbove); err == nil {
+		t.Fatal("expected an error when approaching ∞ from above")
+	}
+}
@@ -2,20 +2,19 @@ package equations
 
 import (
 	"math"
-	"reflect"
 )
 
 type pattern func(*value) bool
 
 func num(n float64) pattern {
 	return func(v *value) bool {
-		return v.op == "num" && v.number == n
+		return v.op == "num" && v.number == n && v.imaginary == 0
 	}
 }
 
 func anyNum(n *float64) pattern {
 	return func(v *value) bool {
-		if v.op == "num" {
+		if v.op == "num" && v.imaginary == 0 {
 			*n = v.number
 			return true
 		}
@@ -23,16 +22,32 @@ func anyNum(n *float64) pattern {
 	}
 }
 
+func anyNumber(n *value) pattern {
+	return func(v *value) bool {
+		if v.op == "num" {
+			*n = *v
+			return true
+		}
+		return false
+	}
+}
+
+func anyRealNumber(n *value) pattern {
+	return func(v *value) bool {
+		return v.imaginary == 0 && anyNumber(n)(v)
+	}
+}
+
 func variable(name string) pattern {
 	return func(val *value) bool {
 		return val.op == "var" && val.name == name
 	}
 }
 
-func anyVariable(factor *float64, name *string, exponent *float64) pattern {
+func anyVariable(factor *value, name *string, exponent *float64) pattern {
 	return func(v *value) bool {
 		if v.op == "var" {
-			*factor = v.number
+			*factor = coefficient(*v)
 			*name = v.name
 			*exponent = v.exponent
 			return true
@@ -41,6 +56,16 @@ func anyVariable(factor *float64, name *string, exponent *float64) pattern {
 	}
 }
 
+func anyConstant(c *value) pattern {
+	return func(v *value) bool {
+		if v.op == "const" {
+			*c = *v
+			return true
+		}
+		return false
+	}
+}
+
 func any(val *value) pattern {
 	return func(v *value) bool {
 		*val = *v
@@ -60,22 +85,23 @@ type PatternMatcher interface {
 }
 
 type removeSubtractionMatcher struct {
-	valParam value
-	number   float64
+	valParam, number value
+	simplifier       *Simplifier
 }
 
 func (sm *removeSubtractionMatcher) Match(val *value) bool {
-	return bin(any(&sm.valParam), "-", anyNum(&sm.number))(val)
+	return bin(any(&sm.valParam), "-", anyNumber(&sm.number))(val)
 }
 
 func (sm *removeSubtractionMatcher) Execute() value {
-	return Add(sm.valParam, Num(-sm.number))
+	return Add(sm.valParam, sm.simplifier.neg(sm.number))
 }
 
 type removeVariableSubtractionMatcher struct {
-	valParam            value
-	varFactor, exponent float64
+	valParam, varFactor value
+	exponent            float64
 	varName             string
+	simplifier          *Simplifier
 }
 
 func (sm *removeVariableSubtractionMatcher) Match(val *value) bool {
@@ -83,82 +109,86 @@ func (sm *removeVariableSubtractionMatcher) Match(val *value) bool {
 }
 
 func (sm *removeVariableSubtractionMatcher) Execute() value {
-	return Add(sm.valParam, Var(-sm.varFactor, sm.varName, sm.exponent))
+	return Add(sm.valParam, term(sm.simplifier.neg(sm.varFactor), sm.varName, sm.exponent))
 }
 
 type removeDivisionMatcher struct {
-	valParam value
-	number   float64
+	valParam, number value
+	simplifier       *Simplifier
 }
 
 func (dm *removeDivisionMatcher) Match(val *value) bool {
-	return bin(any(&dm.valParam), "/", anyNum(&dm.number))(val)
+	return bin(any(&dm.valParam), "/", anyNumber(&dm.number))(val) && dm.simplifier.invertible(dm.number)
 }
 
 func (dm *removeDivisionMatcher) Execute() value {
-	return Mul(dm.valParam, Num(1/dm.number))
+	return Mul(dm.valParam, dm.simplifier.inverse(dm.number))
 }
 
 type removeVariableDivisionMatcher struct {
-	valParam            value
-	varFactor, exponent float64
+	valParam, varFactor value
+	exponent            float64
 	varName             string
+	simplifier          *Simplifier
 }
 
 func (dm *removeVariableDivisionMatcher) Match(val *value) bool {
-	return bin(any(&dm.valParam), "/", anyVariable(&dm.varFactor, &dm.varName, &dm.exponent))(val)
+	return bin(any(&dm.valParam), "/", anyVariable(&dm.varFactor, &dm.varName, &dm.exponent))(val) && dm.simplifier.invertible(dm.varFactor)
 }
 
 func (dm *removeVariableDivisionMatcher) Execute() value {
-	return Mul(dm.valParam, Var(1/dm.varFactor, dm.varName, dm.exponent))
+	return Mul(dm.valParam, term(dm.simplifier.inverse(dm.varFactor), dm.varName, -dm.exponent))
 }
 
 type addMatcher struct {
-	number1, number2 float64
+	number1, number2 value
+	simplifier       *Simplifier
 }
 
 func (am *addMatcher) Match(val *value) bool {
-	return bin(anyNum(&am.number1), "+", anyNum(&am.number2))(val)
+	return bin(anyNumber(&am.number1), "+", anyNumber(&am.number2))(val) && am.simplifier.foldable(am.number1, am.number2)
 }
 
 func (am *addMatcher) Execute() value {
-	return Num(am.number1 + am.number2)
+	return am.simplifier.add(am.number1, am.number2)
 }
 
 type mulMatcher struct {
-	number1, number2 float64
+	number1, number2 value
+	simplifier       *Simplifier
 }
 
 func (mm *mulMatcher) Match(val *value) bool {
-	return bin(anyNum(&mm.number1), "*", anyNum(&mm.number2))(val)
+	return bin(anyNumber(&mm.number1), "*", anyNumber(&mm.number2))(val) && mm.simplifier.foldable(mm.number1, mm.number2)
 }
 
 func (mm *mulMatcher) Execute() value {
-	return Num(mm.number1 * mm.number2)
+	return mm.simplifier.mul(mm.number1, mm.number2)
 }
 
 type powMatcher struct {
-	number1, number2 float64
+	number1, number2 value
+	simplifier       *Simplifier
 }
 
 func (pm *powMatcher) Match(val *value) bool {
-	return bin(anyNum(&pm.number1), "^", anyNum(&pm.number2))(val)
+	return bin(anyNumber(&pm.number1), "^", anyNumber(&pm.number2))(val) && pm.simplifier.powable(pm.number1, pm.number2)
 }
 
 func (pm *powMatcher) Execute() value {
-	return Num(math.Pow(pm.number1, pm.number2))
+	return pm.simplifier.pow(pm.number1, pm.number2)
 }
 
 type returnZeroMatcher struct {
 }
 
 func (mm *returnZeroMatcher) Match(val *value) bool {
-	var val1, val2 value
-	var number, exponent float64
+	var val1, val2, number value
+	var exponent float64
 	var varName string
 	return bin(any(&val1), "*", num(0))(val) ||
 		bin(num(0), "*", any(&val2))(val) ||
-		(anyVariable(&number, &varName, &exponent)(val) && number == 0.0)
+		(anyVariable(&number, &varName, &exponent)(val) && isZeroNumber(number))
 }
 
 func (mm *returnZeroMatcher) Execute() value {
@@ -177,6 +207,20 @@ func (mm *returnOneMatcher) Execute() value {
 	return Num(1)
 }
 
+type zeroExponentMatcher struct {
+	factor   value
+	exponent float64
+	varName  string
+}
+
+func (zm *zeroExponentMatcher) Match(val *value) bool {
+	return anyVariable(&zm.factor, &zm.varName, &zm.exponent)(val) && zm.exponent == 0
+}
+
+func (zm *zeroExponentMatcher) Execute() value {
+	return zm.factor
+}
+
 type returnValueMatcher struct {
 	result value
 }
@@ -193,23 +237,47 @@ func (rvm *returnValueMatcher) Execute() value {
 	return rvm.result
 }
 
+type constantQuotientMatcher struct {
+	rest, numerator, denominator value
+}
+
+func (cm *constantQuotientMatcher) Match(val *value) bool {
+	cm.rest = Num(1)
+	switch {
+	case bin(anyConstant(&cm.numerator), "/", anyConstant(&cm.denominator))(val):
+	case bin(bin(any(&cm.rest), "*", anyConstant(&cm.numerator)), "/", anyConstant(&cm.denominator))(val):
+	case bin(bin(anyConstant(&cm.numerator), "*", any(&cm.rest)), "/", anyConstant(&cm.denominator))(val):
+	default:
+		return false
+	}
+	return Equal(cm.numerator, cm.denominator) && cm.denominator.number != 0
+}
+
+func (cm *constantQuotientMatcher) Execute() value {
+	return cm.rest
+}
+
 type variableMulMatcher struct {
-	number1, number2, exponent float64
-	varName                    string
+	number1, number2 value
+	exponent         float64
+	varName          string
+	simplifier       *Simplifier
 }
 
 func (mm *variableMulMatcher) Match(val *value) bool {
-	return bin(anyVariable(&mm.number1, &mm.varName, &mm.exponent), "*", anyNum(&mm.number2))(val) ||
-		bin(anyNum(&mm.number1), "*", anyVariable(&mm.number2, &mm.varName, &mm.exponent))(val)
+	return bin(anyVariable(&mm.number1, &mm.varName, &mm.exponent), "*", anyRealNumber(&mm.number2))(val) ||
+		bin(anyRealNumber(&mm.number1), "*", anyVariable(&mm.number2, &mm.varName, &mm.exponent))(val)
 }
 
 func (mm *variableMulMatcher) Execute() value {
-	return Var(mm.number1*mm.number2, mm.varName, mm.exponent)
+	return term(mm.simplifier.mul(mm.number1, mm.number2), mm.varName, mm.exponent)
 }
 
 type variableAddMatcher struct {
-	number1, number2, exponent1, exponent2 float64
-	varName1, varName2                     string
+	number1, number2     value
+	exponent1, exponent2 float64
+	varName1, varName2   string
+	simplifier           *Simplifier
 }
 
 func (am *variableAddMatcher) Match(val *value) bool {
@@ -217,12 +285,14 @@ func (am *variableAddMatcher) Match(val *value) bool {
 }
 
 func (am *variableAddMatcher) Execute() value {
-	return Var(am.number1+am.number2, am.varName1, am.exponent1)
+	return term(am.simplifier.add(am.number1, am.number2), am.varName1, am.exponent1)
 }
 
 type variableMulVariableMatcher struct {
-	factor1, exponent1, factor2, exponent2 float64
-	varName1, varName2                     string
+	factor1, factor2     value
+	exponent1, exponent2 float64
+	varName1, varName2   string
+	simplifier           *Simplifier
 }
 
 func (vmvm *variableMulVariableMatcher) Match(val *value) bool {
@@ -230,66 +300,68 @@ func (vmvm *variableMulVariableMatcher) Match(val *value) bool {
 }
 
 func (vmvm *variableMulVariableMatcher) Execute() value {
-	return Var(vmvm.factor1*vmvm.factor2, vmvm.varName1, vmvm.exponent1+vmvm.exponent2)
+	return term(vmvm.simplifier.mul(vmvm.factor1, vmvm.factor2), vmvm.varName1, vmvm.exponent1+vmvm.exponent2)
 }
 
 type distributiveMatcher struct {
-	val1, val2 value
-	number     float64
+	val1, val2, number value
 }
 
 func (dm *distributiveMatcher) Match(val *value) bool {
-	return bin(bin(any(&dm.val1), "+", any(&dm.val2)), "*", anyNum(&dm.number))(val) ||
-		bin(anyNum(&dm.number), "*", bin(any(&dm.val1), "+", any(&dm.val2)))(val)
+	return bin(bin(any(&dm.val1), "+", any(&dm.val2)), "*", anyNumber(&dm.number))(val) ||
+		bin(anyNumber(&dm.number), "*", bin(any(&dm.val1), "+", any(&dm.val2)))(val)
 }
 
 func (dm *distributiveMatcher) Execute() value {
-	return Add(Mul(Num(dm.number), dm.val1), Mul(Num(dm.number), dm.val2))
+	return Add(Mul(dm.number, dm.val1), Mul(dm.number, dm.val2))
 }
 
 type associativeMatcher1 struct {
-	number1, number2, number3, exponent1, exponent2 float64
-	varName1, varName2                              string
+	number1, number2, number3 value
+	exponent1, exponent2      float64
+	varName1, varName2        string
+	simplifier                *Simplifier
 }
 
 func (am *associativeMatcher1) Match(val *value) bool {
-	return bin(bin(anyVariable(&am.number1, &am.varName1, &am.exponent1), "+", anyNum(&am.number2)), "+", anyVariable(&am.number3, &am.varName2, &am.exponent2))(val) && am.exponent1 == am.exponent2
+	return bin(bin(anyVariable(&am.number1, &am.varName1, &am.exponent1), "+", anyNumber(&am.number2)), "+", anyVariable(&am.number3, &am.varName2, &am.exponent2))(val) && am.exponent1 == am.exponent2
 }
 
 func (am *associativeMatcher1) Execute() value {
-	return Add(Var(am.number1+am.number3, am.varName1, am.exponent1), Num(am.number2))
+	return Add(term(am.simplifier.add(am.number1, am.number3), am.varName1, am.exponent1), am.number2)
 }
 
 type associativeMatcher2 struct {
-	number1, number2 float64
-	v                value
+	number1, number2, v value
+	simplifier          *Simplifier
 }
 
 func (am *associativeMatcher2) Match(val *value) bool {
-	return bin(bin(any(&am.v), "+", anyNum(&am.number1)), "+", anyNum(&am.number2))(val)
+	return bin(bin(any(&am.v), "+", anyNumber(&am.number1)), "+", anyNumber(&am.number2))(val) && am.simplifier.foldable(am.number1, am.number2)
 }
 
 func (am *associativeMatcher2) Execute() value {
-	return Add(am.v, Num(am.number1+am.number2))
+	return Add(am.v, am.simplifier.add(am.number1, am.number2))
 }
 
 type associativeMatcher3 struct {
-	number1, number2 float64
-	v                value
+	number1, number2, v value
+	simplifier          *Simplifier
 }
 
 func (am *associativeMatcher3) Match(val *value) bool {
-	return bin(bin(anyNum(&am.number1), "+", any(&am.v)), "+", anyNum(&am.number2))(val)
+	return bin(bin(anyNumber(&am.number1), "+", any(&am.v)), "+", anyNumber(&am.number2))(val) && am.simplifier.foldable(am.number1, am.number2)
 }
 
 func (am *associativeMatcher3) Execute() value {
-	return Add(Num(am.number1+am.number2), am.v)
+	return Add(am.simplifier.add(am.number1, am.number2), am.v)
 }
 
 type associativeMatcher4 struct {
-	number1, number2, exponent1, exponent2 float64
-	varName1, varName2                     string
-	v                                      value
+	number1, number2, v  value
+	exponent1, exponent2 float64
+	varName1, varName2   string
+	simplifier           *Simplifier
 }
 
 func (am *associativeMatcher4) Match(val *value) bool {
@@ -297,33 +369,33 @@ func (am *associativeMatcher4) Match(val *value) bool {
 }
 
 func (am *associativeMatcher4) Execute() value {
-	return Add(am.v, Var(am.number1+am.number2, am.varName1, am.exponent1))
+	return Add(am.v, term(am.simplifier.add(am.number1, am.number2), am.varName1, am.exponent1))
 }
 
 type associativeMatcher5 struct {
-	number1, number2 float64
-	v                value
+	number1, number2, v value
+	simplifier          *Simplifier
 }
 
 func (am *associativeMatcher5) Match(val *value) bool {
-	return bin(anyNum(&am.number1), "+", bin(any(&am.v), "+", anyNum(&am.number2)))(val)
+	return bin(anyNumber(&am.number1), "+", bin(any(&am.v), "+", anyNumber(&am.number2)))(val) && am.simplifier.foldable(am.number1, am.number2)
 }
 
 func (am *associativeMatcher5) Execute() value {
-	return Add(am.v, Num(am.number1+am.number2))
+	return Add(am.v, am.simplifier.add(am.number1, am.number2))
 }
 
 type associativeMatcher6 struct {
-	number1, number2 float64
-	v                value
+	number1, number2, v value
+	simplifier          *Simplifier
 }
 
 func (am *associativeMatcher6) Match(val *value) bool {
-	return bin(anyNum(&am.number1), "+", bin(anyNum(&am.number2), "+", any(&am.v)))(val)
+	return bin(anyNumber(&am.number1), "+", bin(anyNumber(&am.number2), "+", any(&am.v)))(val) && am.simplifier.foldable(am.number1, am.number2)
 }
 
 func (am *associativeMatcher6) Execute() value {
-	return Add(am.v, Num(am.number1+am.number2))
+	return Add(am.v, am.simplifier.add(am.number1, am.number2))
 }
 
 type binomial1Matcher struct {
@@ -344,36 +416,81 @@ type binomial3Matcher struct {
 }
 
 func (bm *binomial3Matcher) Match(val *value) bool {
-	return bin(bin(any(&bm.val1), "+", any(&bm.val2)), "*", bin(any(&bm.val3), "-", any(&bm.val4)))(val) && reflect.DeepEqual(bm.val1, bm.val3) && reflect.DeepEqual(bm.val2, bm.val4) ||
-		bin(bin(any(&bm.val1), "+", anyNum(&bm.number1)), "*", bin(any(&bm.val3), "+", anyNum(&bm.number2)))(val) && reflect.DeepEqual(bm.val1, bm.val3) && math.Abs(bm.number1) == math.Abs(bm.number2) || bm.number1 > 0 && bm.number2 < 0
+	return bin(bin(any(&bm.val1), "+", any(&bm.val2)), "*", bin(any(&bm.val3), "-", any(&bm.val4)))(val) && EqualCommutative(bm.val1, bm.val3) && EqualCommutative(bm.val2, bm.val4) ||
+		bin(bin(any(&bm.val1), "+", anyNum(&bm.number1)), "*", bin(any(&bm.val3), "+", anyNum(&bm.number2)))(val) && EqualCommutative(bm.val1, bm.val3) && math.Abs(bm.number1) == math.Abs(bm.number2) || bm.number1 > 0 && bm.number2 < 0
 }
 
 func (bm *binomial3Matcher) Execute() value {
 	return Sub(Pow(bm.val1, Num(2)), Pow(bm.val2, Num(2)))
 }
 
-var Matchers = []PatternMatcher{
-	&removeSubtractionMatcher{},
-	&removeVariableSubtractionMatcher{},
-	&removeDivisionMatcher{},
-	&removeVariableDivisionMatcher{},
-	&addMatcher{},
-	&mulMatcher{},
-	&powMatcher{},
-	&returnZeroMatcher{},
-	&returnOneMatcher{},
-	&returnValueMatcher{},
-	&variableMulMatcher{},
-	&variableMulVariableMatcher{},
-	&variableAddMatcher{},
-	// &variableAndNumberMulMatcher{},
-	&distributiveMatcher{},
-	&associativeMatcher1{},
-	&associativeMatcher2{},
-	&associativeMatcher3{},
-	&associativeMatcher4{},
-	&associativeMatcher5{},
-	&associativeMatcher6{},
-	&binomial1Matcher{},
-	&binomial3Matcher{},
+type functionMatcher struct {
+	name       string
+	number     value
+	simplifier *Simplifier
+}
+
+func (fm *functionMatcher) Match(val *value) bool {
+	if _, unary := functions[val.op]; unary && anyNumber(&fm.number)(val.left) && fm.simplifier.applicable(val.op, fm.number) {
+		fm.name = val.op
+		return true
+	}
+	return false
+}
+
+func (fm *functionMatcher) Execute() value {
+	return fm.simplifier.apply(fm.name, fm.number)
+}
+
+type inverseFunctionMatcher struct {
+	result value
+}
+
+func (ifm *inverseFunctionMatcher) Match(val *value) bool {
+	if inverse, invertible := inverseFunctions[val.op]; invertible && val.left.op == inverse {
+		ifm.result = *val.left.left
+		return true
+	}
+	return false
+}
+
+func (ifm *inverseFunctionMatcher) Execute() value {
+	return ifm.result
+}
+
+var Matchers = newMatchers(nil)
+
+func newMatchers(s *Simplifier) []PatternMatcher {
+	return []PatternMatcher{
+		&matrixAddMatcher{},
+		&matrixMulMatcher{},
+		&matrixScaleMatcher{},
+		&removeSubtractionMatcher{simplifier: s},
+		&removeVariableSubtractionMatcher{simplifier: s},
+		&removeDivisionMatcher{simplifier: s},
+		&removeVariableDivisionMatcher{simplifier: s},
+		&addMatcher{simplifier: s},
+		&mulMatcher{simplifier: s},
+		&powMatcher{simplifier: s},
+		&returnZeroMatcher{},
+		&returnOneMatcher{},
+		&zeroExponentMatcher{},
+		&returnValueMatcher{},
+		&constantQuotientMatcher{},
+		&variableMulMatcher{simplifier: s},
+		&variableMulVariableMatcher{simplifier: s},
+		&variableAddMatcher{simplifier: s},
+		// &variableAndNumberMulMatcher{},
+		&distributiveMatcher{},
+		&associativeMatcher1{simplifier: s},
+		&associativeMatcher2{simplifier: s},
+		&associativeMatcher3{simplifier: s},
+		&associativeMatcher4{simplifier: s},
+		&associativeMatcher5{simplifier: s},
+		&associativeMatcher6{simplifier: s},
+		&binomial1Matcher{},
+		&binomial3Matcher{},
+		&functionMatcher{simplifier: s},
+		&inverseFunctionMatcher{},
+	}
 }
@@ -1,6 +1,7 @@
 package equations
 
 import (
+	"math"
 	"reflect"
 	"testing"
 )
@@ -58,13 +59,27 @@ func TestRemoveVariableDivisionMatcher(t *testing.T) {
 		t.Fatal("matcher should match")
 	}
 
-	expected := Mul(Num(4), Var(1.0/2.0, "x", 1))
+	expected := Mul(Num(4), Var(1.0/2.0, "x", -1))
 	result := matcher.Execute()
 	if !reflect.DeepEqual(result, expected) {
 		t.Fatalf("expect %v to be %v", result, expected)
 	}
 }
 
+func TestRemoveVariableDivisionMatcher_keepsQuotientValue(t *testing.T) {
+	for _, exponent := range []float64{1, 2, -1} {
+		division := Div(Num(4), Var(2, "x", exponent))
+
+		result, err := Evaluate(division.execute(), map[string]float64{"x": 4})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if expected := 2 / math.Pow(4, exponent); result != expected {
+			t.Fatalf("expected 4/(2x^%v) at x = 4 to be %v, got %v", exponent, expected, result)
+		}
+	}
+}
+
 func TestAddMatcher(t *testing.T) {
 	sum := Add(Num(4), Num(2))
 
@@ -170,6 +185,21 @@ func TestReturnOneMatcher(t *testing.T) {
 	}
 }
 
+func TestZeroExponentMatcher(t *testing.T) {
+	constant := Var(3, "x", 0)
+
+	matcher := zeroExponentMatcher{}
+	if !matcher.Match(&constant) {
+		t.Fatal("matcher should match")
+	}
+
+	expected := Num(3)
+	result := matcher.Execute()
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("expect %v to be %v", result, expected)
+	}
+}
+
 func TestReturnValueMatcher_mulWith1_1(t *testing.T) {
 	product := Mul(Add(Var(2, "x", 1), Num(4)), Num(1))
 
@@ -397,3 +427,33 @@ func TestBinomial3_2(t *testing.T) {
 		t.Fatalf("expect %v to be %v", result, expected)
 	}
 }
+
+func TestFunctionMatcher(t *testing.T) {
+	sine := Sin(Num(0))
+
+	matcher := functionMatcher{}
+	if !matcher.Match(&sine) {
+		t.Fatal("matcher should match")
+	}
+
+	expected := Num(0)
+	result := matcher.Execute()
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("expect %v to be %v", result, expected)
+	}
+}
+
+func TestInverseFunctionMatcher(t *testing.T) {
+	logarithm := Ln(Exp(Var(2, "x", 1)))
+
+	matcher := inverseFunctionMatcher{}
+	if !matcher.Match(&logarithm) {
+		t.Fatal("matcher should match")
+	}
+
+	expected := Var(2, "x", 1)
+	result := matcher.Execute()
+	if !reflect.DeepEqual(result, expected) {
+		t.Fatalf("expect %v to be %v", result, expected)
+	}
+}
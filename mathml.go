@@ -0,0 +1,423 @@
+package equations
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+const mathMLNamespace = "http://www.w3.org/1998/Math/MathML"
+
+var contentOperators = map[string]string{
+	"+": "plus",
+	"-": "minus",
+	"*": "times",
+	"/": "divide",
+	"^": "power",
+}
+
+var precedences = map[string]int{
+	"+": 1,
+	"-": 1,
+	"*": 2,
+	"/": 3,
+	"^": 4,
+}
+
+func formatNumber(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func escape(s string) string {
+	var b strings.Builder
+	xml.EscapeText(&b, []byte(s))
+	return b.String()
+}
+
+func mathElement(body string) string {
+	return `<math xmlns="` + mathMLNamespace + `">` + body + `</math>`
+}
+
+func MathML(val value) (string, error) {
+	body, err := presentation(val)
+	if err != nil {
+		return "", err
+	}
+	return mathElement(body), nil
+}
+
+func EquationMathML(eq equation) (string, error) {
+	left, err := presentation(eq.left)
+	if err != nil {
+		return "", err
+	}
+	right, err := presentation(eq.right)
+	if err != nil {
+		return "", err
+	}
+	return mathElement("<mrow>" + left + "<mo>=</mo>" + right + "</mrow>"), nil
+}
+
+func presentationIdentifier(name string) string {
+	switch name {
+	case "π":
+		return "<mi>&#x3C0;</mi>"
+	case "ℯ":
+		return "<mi>&#x212F;</mi>"
+	}
+	return "<mi>" + escape(name) + "</mi>"
+}
+
+func presentation(val value) (string, error) {
+	if _, unary := functions[val.op]; unary {
+		arg, err := presentation(*val.left)
+		if err != nil {
+			return "", err
+		}
+		if val.op == "abs" {
+			return "<mrow><mo>|</mo>" + arg + "<mo>|</mo></mrow>", nil
+		}
+		return "<mrow><mi>" + val.op + "</mi><mo>&#x2061;</mo><mrow><mo>(</mo>" + arg + "<mo>)</mo></mrow></mrow>", nil
+	}
+
+	switch val.op {
+	default:
+		return "", errors.New("cannot write MathML for operator " + val.op)
+	case "num":
+		switch {
+		case val.imaginary == 0:
+			return "<mn>" + formatNumber(val.number) + "</mn>", nil
+		case val.number == 0:
+			return "<mrow><mn>" + formatNumber(val.imaginary) + "</mn><mo>&#x2062;</mo><mi>i</mi></mrow>", nil
+		case val.imaginary < 0:
+			return "<mrow><mn>" + formatNumber(val.number) + "</mn><mo>-</mo><mn>" + formatNumber(-val.imaginary) + "</mn><mo>&#x2062;</mo><mi>i</mi></mrow>", nil
+		default:
+			return "<mrow><mn>" + formatNumber(val.number) + "</mn><mo>+</mo><mn>" + formatNumber(val.imaginary) + "</mn><mo>&#x2062;</mo><mi>i</mi></mrow>", nil
+		}
+	case "const":
+		return presentationIdentifier(val.name), nil
+	case "var":
+		term := presentationIdentifier(val.name)
+		if val.exponent != 1 {
+			term = "<msup>" + term + "<mn>" + formatNumber(val.exponent) + "</mn></msup>"
+		}
+		if val.number == 1 {
+			return term, nil
+		}
+		return "<mrow><mn>" + formatNumber(val.number) + "</mn><mo>&#x2062;</mo>" + term + "</mrow>", nil
+	case "/":
+		numerator, err := presentation(*val.left)
+		if err != nil {
+			return "", err
+		}
+		denominator, err := presentation(*val.right)
+		if err != nil {
+			return "", err
+		}
+		return "<mfrac>" + numerator + denominator + "</mfrac>", nil
+	case "^":
+		base, err := presentationOperand(*val.left, val.op, true)
+		if err != nil {
+			return "", err
+		}
+		exponent, err := presentation(*val.right)
+		if err != nil {
+			return "", err
+		}
+		return "<msup>" + base + exponent + "</msup>", nil
+	case "+", "-", "*":
+		operator := val.op
+		if operator == "*" {
+			operator = "&#x22C5;"
+		}
+		l, err := presentationOperand(*val.left, val.op, false)
+		if err != nil {
+			return "", err
+		}
+		r, err := presentationOperand(*val.right, val.op, true)
+		if err != nil {
+			return "", err
+		}
+		return "<mrow>" + l + "<mo>" + operator + "</mo>" + r + "</mrow>", nil
+	}
+}
+
+func presentationOperand(val value, parent string, right bool) (string, error) {
+	operand, err := presentation(val)
+	if err != nil {
+		return "", err
+	}
+	precedence, binary := precedences[val.op]
+	if val.op == "var" && (val.number != 1 || val.exponent != 1) && parent == "^" {
+		binary, precedence = true, 0
+	}
+	if binary && (val.op != "/" || parent == "^") && (precedence < precedences[parent] || right && precedence == precedences[parent] && parent != "+" && parent != "*") {
+		return "<mrow><mo>(</mo>" + operand + "<mo>)</mo></mrow>", nil
+	}
+	return operand, nil
+}
+
+func ContentMathML(val value) (string, error) {
+	body, err := content(val)
+	if err != nil {
+		return "", err
+	}
+	return mathElement(body), nil
+}
+
+func EquationContentMathML(eq equation) (string, error) {
+	left, err := content(eq.left)
+	if err != nil {
+		return "", err
+	}
+	right, err := content(eq.right)
+	if err != nil {
+		return "", err
+	}
+	return mathElement("<apply><eq/>" + left + right + "</apply>"), nil
+}
+
+func content(val value) (string, error) {
+	if _, unary := functions[val.op]; unary {
+		arg, err := content(*val.left)
+		if err != nil {
+			return "", err
+		}
+		return "<apply><" + val.op + "/>" + arg + "</apply>", nil
+	}
+
+	switch val.op {
+	default:
+		return "", errors.New("cannot write MathML for operator " + val.op)
+	case "num":
+		if val.imaginary != 0 {
+			return `<cn type="complex-cartesian">` + formatNumber(val.number) + "<sep/>" + formatNumber(val.imaginary) + "</cn>", nil
+		}
+		return "<cn>" + formatNumber(val.number) + "</cn>", nil
+	case "const":
+		switch val.name {
+		case "π":
+			return "<pi/>", nil
+		case "ℯ":
+			return "<exponentiale/>", nil
+		}
+		return "<ci>" + escape(val.name) + "</ci>", nil
+	case "var":
+		term := "<ci>" + escape(val.name) + "</ci>"
+		if val.exponent != 1 {
+			term = "<apply><power/>" + term + "<cn>" + formatNumber(val.exponent) + "</cn></apply>"
+		}
+		if val.number == 1 {
+			return term, nil
+		}
+		return "<apply><times/><cn>" + formatNumber(val.number) + "</cn>" + term + "</apply>", nil
+	case "+", "-", "*", "/", "^":
+		l, err := content(*val.left)
+		if err != nil {
+			return "", err
+		}
+		r, err := content(*val.right)
+		if err != nil {
+			return "", err
+		}
+		return "<apply><" + contentOperators[val.op] + "/>" + l + r + "</apply>", nil
+	}
+}
+
+type mathMLNode struct {
+	name     string
+	attrs    map[string]string
+	text     []string
+	children []*mathMLNode
+}
+
+func parseMathMLNode(decoder *xml.Decoder, start xml.StartElement) (*mathMLNode, error) {
+	node := &mathMLNode{name: start.Name.Local, attrs: make(map[string]string), text: []string{""}}
+	for _, attr := range start.Attr {
+		node.attrs[attr.Name.Local] = attr.Value
+	}
+	for {
+		token, err := decoder.Token()
+		if err != nil {
+			return nil, err
+		}
+		switch t := token.(type) {
+		case xml.StartElement:
+			child, err := parseMathMLNode(decoder, t)
+			if err != nil {
+				return nil, err
+			}
+			node.children = append(node.children, child)
+			node.text = append(node.text, "")
+		case xml.CharData:
+			node.text[len(node.text)-1] += strings.TrimSpace(string(t))
+		case xml.EndElement:
+			return node, nil
+		}
+	}
+}
+
+func parseMathML(s string) (*mathMLNode, error) {
+	decoder := xml.NewDecoder(strings.NewReader(s))
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return nil, errors.New("no MathML element found")
+		}
+		if err != nil {
+			return nil, err
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			node, err := parseMathMLNode(decoder, start)
+			if err != nil {
+				return nil, err
+			}
+			if node.name == "math" {
+				if len(node.children) != 1 {
+					return nil, fmt.Errorf("<math> must contain exactly one element, found %d", len(node.children))
+				}
+				return node.children[0], nil
+			}
+			return node, nil
+		}
+	}
+}
+
+func ParseContentMathML(s string) (value, error) {
+	node, err := parseMathML(s)
+	if err != nil {
+		return value{}, err
+	}
+	return fromContent(node)
+}
+
+func ParseContentMathMLEquation(s string) (equation, error) {
+	node, err := parseMathML(s)
+	if err != nil {
+		return equation{}, err
+	}
+	if node.name != "apply" || len(node.children) != 3 || node.children[0].name != "eq" {
+		return equation{}, errors.New("expected <apply><eq/>…</apply> for an equation")
+	}
+	left, err := fromContent(node.children[1])
+	if err != nil {
+		return equation{}, err
+	}
+	right, err := fromContent(node.children[2])
+	if err != nil {
+		return equation{}, err
+	}
+	return NewEquation(left, right), nil
+}
+
+func parseNumber(s string) (float64, error) {
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q in <cn>", s)
+	}
+	return f, nil
+}
+
+func fromContent(node *mathMLNode) (value, error) {
+	switch node.name {
+	default:
+		return value{}, fmt.Errorf("unsupported MathML element <%v>", node.name)
+	case "cn":
+		if node.attrs["type"] == "complex-cartesian" {
+			if len(node.text) != 2 {
+				return value{}, errors.New("complex <cn> needs exactly one <sep/>")
+			}
+			re, err := parseNumber(node.text[0])
+			if err != nil {
+				return value{}, err
+			}
+			im, err := parseNumber(node.text[1])
+			if err != nil {
+				return value{}, err
+			}
+			return Complex(re, im), nil
+		}
+		if len(node.children) > 0 {
+			return value{}, fmt.Errorf("unsupported <cn> type %q", node.attrs["type"])
+		}
+		f, err := parseNumber(node.text[0])
+		return Num(f), err
+	case "ci":
+		if len(node.children) > 0 || node.text[0] == "" {
+			return value{}, errors.New("<ci> must contain a plain identifier")
+		}
+		return Var(1, node.text[0], 1), nil
+	case "pi":
+		return Pi(), nil
+	case "exponentiale":
+		return E(), nil
+	case "apply":
+		return fromApply(node)
+	}
+}
+
+func fromApply(node *mathMLNode) (value, error) {
+	if len(node.children) < 2 {
+		return value{}, errors.New("<apply> needs an operator and at least one operand")
+	}
+	operator := node.children[0].name
+	operands := make([]value, 0, len(node.children)-1)
+	for _, child := range node.children[1:] {
+		operand, err := fromContent(child)
+		if err != nil {
+			return value{}, err
+		}
+		operands = append(operands, operand)
+	}
+
+	if _, unary := functions[operator]; unary {
+		if len(operands) != 1 {
+			return value{}, fmt.Errorf("<%v/> takes exactly one operand", operator)
+		}
+		return function(operator, operands[0]), nil
+	}
+
+	switch operator {
+	default:
+		return value{}, fmt.Errorf("unsupported MathML operator <%v/>", operator)
+	case "plus":
+		return fold(operands, Add), nil
+	case "times":
+		if len(operands) == 2 && operands[0].op == "num" && operands[0].imaginary == 0 && operands[1].op == "var" && operands[1].number == 1 {
+			return Var(operands[0].number, operands[1].name, operands[1].exponent), nil
+		}
+		return fold(operands, Mul), nil
+	case "minus":
+		switch len(operands) {
+		case 1:
+			return negate(operands[0]), nil
+		case 2:
+			return Sub(operands[0], operands[1]), nil
+		}
+		return value{}, errors.New("<minus/> takes one or two operands")
+	case "divide":
+		if len(operands) != 2 {
+			return value{}, errors.New("<divide/> takes exactly two operands")
+		}
+		return Div(operands[0], operands[1]), nil
+	case "power":
+		if len(operands) != 2 {
+			return value{}, errors.New("<power/> takes exactly two operands")
+		}
+		if operands[0].op == "var" && operands[0].number == 1 && operands[0].exponent == 1 && operands[1].op == "num" && operands[1].imaginary == 0 {
+			return Var(1, operands[0].name, operands[1].number), nil
+		}
+		return Pow(operands[0], operands[1]), nil
+	}
+}
+
+func fold(operands []value, op BinaryOp) value {
+	result := operands[0]
+	for _, operand := range operands[1:] {
+		result = op(result, operand)
+	}
+	return result
+}
@@ -0,0 +1,121 @@
+package equations_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/gossie/equations"
+)
+
+func TestMathML(t *testing.T) {
+	result, err := equations.MathML(equations.Div(equations.Add(equations.Var(2, "x", 2), equations.Num(1)), equations.Mul(equations.Sub(equations.Var(1, "y", 1), equations.Num(3)), equations.Num(4))))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `<math xmlns="http://www.w3.org/1998/Math/MathML"><mfrac><mrow><mrow><mn>2</mn><mo>&#x2062;</mo><msup><mi>x</mi><mn>2</mn></msup></mrow><mo>+</mo><mn>1</mn></mrow><mrow><mrow><mo>(</mo><mrow><mi>y</mi><mo>-</mo><mn>3</mn></mrow><mo>)</mo></mrow><mo>&#x22C5;</mo><mn>4</mn></mrow></mfrac></math>`
+	if result != expected {
+		t.Fatalf("expected %v to be %v", result, expected)
+	}
+}
+
+func TestEquationMathML(t *testing.T) {
+	result, err := equations.EquationMathML(equations.NewEquation(equations.Sin(equations.Pi()), equations.Num(0)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `<math xmlns="http://www.w3.org/1998/Math/MathML"><mrow><mrow><mi>sin</mi><mo>&#x2061;</mo><mrow><mo>(</mo><mi>&#x3C0;</mi><mo>)</mo></mrow></mrow><mo>=</mo><mn>0</mn></mrow></math>`
+	if result != expected {
+		t.Fatalf("expected %v to be %v", result, expected)
+	}
+}
+
+func TestContentMathML(t *testing.T) {
+	result, err := equations.ContentMathML(equations.Sub(equations.Var(3, "x", 2), equations.Div(equations.Num(1), equations.Var(1, "y", 1))))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `<math xmlns="http://www.w3.org/1998/Math/MathML"><apply><minus/><apply><times/><cn>3</cn><apply><power/><ci>x</ci><cn>2</cn></apply></apply><apply><divide/><cn>1</cn><ci>y</ci></apply></apply></math>`
+	if result != expected {
+		t.Fatalf("expected %v to be %v", result, expected)
+	}
+}
+
+func TestContentMathML_eulerNumberAndVariableE(t *testing.T) {
+	result, err := equations.ContentMathML(equations.Mul(equations.E(), equations.Var(1, "e", 1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := `<math xmlns="http://www.w3.org/1998/Math/MathML"><apply><times/><exponentiale/><ci>e</ci></apply></math>`
+	if result != expected {
+		t.Fatalf("expected %v to be %v", result, expected)
+	}
+}
+
+func TestMathML_unknownOperator(t *testing.T) {
+	var empty equations.LimitResult
+
+	if _, err := equations.MathML(empty.Value); err == nil {
+		t.Fatal("expected presentation MathML of an empty value to fail")
+	}
+	if _, err := equations.ContentMathML(equations.Add(equations.Num(1), empty.Value)); err == nil {
+		t.Fatal("expected content MathML of an empty value to fail")
+	}
+}
+
+func TestContentMathML_roundTrip(t *testing.T) {
+	eq := equations.NewEquation(
+		equations.Add(equations.Mul(equations.Var(-2.5, "x", 3), equations.Exp(equations.Var(1, "y", 1))), equations.Pow(equations.Add(equations.Var(1, "z", 1), equations.Num(1)), equations.Num(0.5))),
+		equations.Sub(equations.Complex(1, -2), equations.Ln(equations.Mul(equations.Num(2), equations.Pi()))),
+	)
+
+	written, err := equations.EquationContentMathML(eq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	parsed, err := equations.ParseContentMathMLEquation(written)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if parsed.String() != eq.String() {
+		t.Fatalf("expected %v to be %v", parsed, eq)
+	}
+}
+
+func TestParseContentMathML(t *testing.T) {
+	result, err := equations.ParseContentMathML(`
+		<math xmlns="http://www.w3.org/1998/Math/MathML">
+			<apply><plus/>
+				<ci>a</ci>
+				<apply><minus/><cn>2</cn></apply>
+				<apply><power/><ci>b</ci><cn>2</cn></apply>
+			</apply>
+		</math>`)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.String() != "((1.000000a + -2.000000) + 1.000000b^2)" {
+		t.Fatalf("expected %v to be ((1.000000a + -2.000000) + 1.000000b^2)", result)
+	}
+}
+
+func TestParseContentMathML_unsupported(t *testing.T) {
+	tests := []struct {
+		input, message string
+	}{
+		{`<math><apply><root/><cn>2</cn></apply></math>`, "unsupported MathML operator <root/>"},
+		{`<math><matrix/></math>`, "unsupported MathML element <matrix>"},
+		{`<math><cn>abc</cn></math>`, `invalid number "abc" in <cn>`},
+		{`<math><apply><divide/><cn>1</cn></apply></math>`, "<divide/> takes exactly two operands"},
+	}
+
+	for _, test := range tests {
+		_, err := equations.ParseContentMathML(test.input)
+		if err == nil || !strings.Contains(err.Error(), test.message) {
+			t.Fatalf("expected error %v for %v, got %v", test.message, test.input, err)
+		}
+	}
+}
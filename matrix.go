@@ -0,0 +1,445 @@
+package equations
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+type Matrix struct {
+	rows, columns int
+	entries       []value
+}
+
+func NewMatrix(rows, columns int, entries ...value) (*Matrix, error) {
+	if rows <= 0 || columns <= 0 {
+		return nil, errors.New("a matrix needs at least one row and one column")
+	}
+	if len(entries) != rows*columns {
+		return nil, fmt.Errorf("a %dx%d matrix needs %d entries, got %d", rows, columns, rows*columns, len(entries))
+	}
+	return &Matrix{rows, columns, append([]value(nil), entries...)}, nil
+}
+
+func Vector(entries ...value) *Matrix {
+	return &Matrix{len(entries), 1, append([]value(nil), entries...)}
+}
+
+func VariableVector(names ...string) *Matrix {
+	entries := make([]value, len(names))
+	for i, name := range names {
+		entries[i] = Var(1, name, 1)
+	}
+	return &Matrix{len(names), 1, entries}
+}
+
+func Identity(n int) *Matrix {
+	m := zeroMatrix(n, n)
+	for i := 0; i < n; i++ {
+		m.set(i, i, Num(1))
+	}
+	return m
+}
+
+func zeroMatrix(rows, columns int) *Matrix {
+	entries := make([]value, rows*columns)
+	for i := range entries {
+		entries[i] = Num(0)
+	}
+	return &Matrix{rows, columns, entries}
+}
+
+func (m *Matrix) Value() value {
+	var rows *value
+	for i := m.rows - 1; i >= 0; i-- {
+		var row *value
+		for j := m.columns - 1; j >= 0; j-- {
+			entry := m.At(i, j)
+			row = &value{op: "row", left: &entry, right: row}
+		}
+		rows = &value{op: "matrix", left: row, right: rows}
+	}
+	return *rows
+}
+
+func MatrixOf(val value) (*Matrix, error) {
+	if val.op != "matrix" {
+		return nil, errors.New(val.String() + " is not a matrix")
+	}
+
+	result := &Matrix{}
+	for rows := &val; rows != nil; rows = rows.right {
+		if rows.op != "matrix" || rows.left == nil || rows.left.op != "row" {
+			return nil, errors.New("malformed matrix " + val.String())
+		}
+		columns := 0
+		for row := rows.left; row != nil; row = row.right {
+			if row.op != "row" {
+				return nil, errors.New("malformed matrix " + val.String())
+			}
+			result.entries = append(result.entries, *row.left)
+			columns++
+		}
+		if result.rows > 0 && columns != result.columns {
+			return nil, errors.New("rows of " + val.String() + " differ in length")
+		}
+		result.columns = columns
+		result.rows++
+	}
+	return result, nil
+}
+
+func containsMatrix(val value) bool {
+	return val.op == "matrix" ||
+		val.left != nil && containsMatrix(*val.left) ||
+		val.right != nil && containsMatrix(*val.right)
+}
+
+func (m *Matrix) Rows() int {
+	return m.rows
+}
+
+func (m *Matrix) Columns() int {
+	return m.columns
+}
+
+func (m *Matrix) At(i, j int) value {
+	return m.entries[i*m.columns+j]
+}
+
+func (m *Matrix) set(i, j int, val value) {
+	m.entries[i*m.columns+j] = val
+}
+
+func (m *Matrix) String() string {
+	rows := make([]string, m.rows)
+	for i := range rows {
+		row := make([]string, m.columns)
+		for j := range row {
+			row[j] = m.At(i, j).String()
+		}
+		rows[i] = "[" + strings.Join(row, ", ") + "]"
+	}
+	return "[" + strings.Join(rows, ", ") + "]"
+}
+
+func (m *Matrix) Transpose() *Matrix {
+	result := zeroMatrix(m.columns, m.rows)
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < m.columns; j++ {
+			result.set(j, i, m.At(i, j))
+		}
+	}
+	return result
+}
+
+func (m *Matrix) Add(other *Matrix) (*Matrix, error) {
+	if m.rows != other.rows || m.columns != other.columns {
+		return nil, fmt.Errorf("cannot add a %dx%d and a %dx%d matrix", m.rows, m.columns, other.rows, other.columns)
+	}
+	return m.combine(other, Add).apply(simplifyEntry), nil
+}
+
+func (m *Matrix) Scale(factor value) *Matrix {
+	return m.apply(func(entry value) value {
+		return Mul(factor, entry)
+	}).apply(simplifyEntry)
+}
+
+func (m *Matrix) Mul(other *Matrix) (*Matrix, error) {
+	if m.columns != other.rows {
+		return nil, fmt.Errorf("cannot multiply a %dx%d and a %dx%d matrix", m.rows, m.columns, other.rows, other.columns)
+	}
+	return m.product(other).apply(simplifyEntry), nil
+}
+
+func (m *Matrix) apply(f func(value) value) *Matrix {
+	result := zeroMatrix(m.rows, m.columns)
+	for i, entry := range m.entries {
+		result.entries[i] = f(entry)
+	}
+	return result
+}
+
+func (m *Matrix) combine(other *Matrix, f func(value, value) value) *Matrix {
+	result := zeroMatrix(m.rows, m.columns)
+	for i := range result.entries {
+		result.entries[i] = f(m.entries[i], other.entries[i])
+	}
+	return result
+}
+
+func (m *Matrix) product(other *Matrix) *Matrix {
+	result := zeroMatrix(m.rows, other.columns)
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < other.columns; j++ {
+			terms := make([]value, 0, m.columns)
+			for k := 0; k < m.columns; k++ {
+				if isZero(m.At(i, k)) || isZero(other.At(k, j)) {
+					continue
+				}
+				terms = append(terms, Mul(m.At(i, k), other.At(k, j)))
+			}
+			result.set(i, j, sum(terms))
+		}
+	}
+	return result
+}
+
+func simplifyEntry(val value) value {
+	if simplified, _, err := SimplifyRational(val); err == nil {
+		return simplified
+	}
+	return val.execute()
+}
+
+func (m *Matrix) swapRows(i, k int) {
+	for j := 0; j < m.columns; j++ {
+		a, b := m.At(i, j), m.At(k, j)
+		m.set(i, j, b)
+		m.set(k, j, a)
+	}
+}
+
+// pivot prefers the numeric entry of largest magnitude, which keeps
+// elimination stable and is certainly nonzero, over a symbolic one.
+func (m *Matrix) pivot(column, from int) int {
+	best, symbolic, largest := -1, -1, 0.0
+	for i := from; i < m.rows; i++ {
+		entry := m.At(i, column)
+		switch {
+		case isZero(entry):
+		case entry.op != "num":
+			if symbolic < 0 {
+				symbolic = i
+			}
+		case math.Hypot(entry.number, entry.imaginary) > largest:
+			best, largest = i, math.Hypot(entry.number, entry.imaginary)
+		}
+	}
+	if best < 0 {
+		return symbolic
+	}
+	return best
+}
+
+func (m *Matrix) Determinant() (value, error) {
+	if m.rows != m.columns {
+		return value{}, fmt.Errorf("cannot take the determinant of a %dx%d matrix", m.rows, m.columns)
+	}
+
+	// Bareiss elimination: every division is exact, so entries stay
+	// polynomial when the input is.
+	a := m.apply(simplifyEntry)
+	n := a.rows
+	negate := false
+	previous := Num(1)
+	for k := 0; k < n-1; k++ {
+		row := a.pivot(k, k)
+		if row < 0 {
+			return Num(0), nil
+		}
+		if row != k {
+			a.swapRows(k, row)
+			negate = !negate
+		}
+		for i := k + 1; i < n; i++ {
+			for j := k + 1; j < n; j++ {
+				cross := Sub(Mul(a.At(k, k), a.At(i, j)), Mul(a.At(i, k), a.At(k, j)))
+				a.set(i, j, simplifyEntry(Div(cross, previous)))
+			}
+		}
+		previous = a.At(k, k)
+	}
+
+	det := a.At(n-1, n-1)
+	if negate {
+		det = Mul(Num(-1), det)
+	}
+	return simplifyEntry(det), nil
+}
+
+func (m *Matrix) Inverse() (*Matrix, error) {
+	if m.rows != m.columns {
+		return nil, fmt.Errorf("cannot invert a %dx%d matrix", m.rows, m.columns)
+	}
+	augmented, err := m.augment(Identity(m.rows))
+	if err != nil {
+		return nil, err
+	}
+	if err := augmented.eliminate(); err != nil {
+		return nil, err
+	}
+	return augmented.slice(m.columns), nil
+}
+
+func (m *Matrix) Solve(b *Matrix) (*Matrix, error) {
+	if m.rows != m.columns {
+		return nil, fmt.Errorf("cannot solve a %dx%d system", m.rows, m.columns)
+	}
+	if b.rows != m.rows || b.columns != 1 {
+		return nil, fmt.Errorf("cannot solve a %dx%d system for a %dx%d right-hand side", m.rows, m.columns, b.rows, b.columns)
+	}
+	augmented, err := m.augment(b)
+	if err != nil {
+		return nil, err
+	}
+	if err := augmented.eliminate(); err != nil {
+		return nil, err
+	}
+	return augmented.slice(m.columns), nil
+}
+
+func (m *Matrix) augment(other *Matrix) (*Matrix, error) {
+	if other.rows != m.rows {
+		return nil, fmt.Errorf("cannot augment a %d-row matrix with a %d-row matrix", m.rows, other.rows)
+	}
+	result := zeroMatrix(m.rows, m.columns+other.columns)
+	for i := 0; i < m.rows; i++ {
+		for j := 0; j < m.columns; j++ {
+			result.set(i, j, simplifyEntry(m.At(i, j)))
+		}
+		for j := 0; j < other.columns; j++ {
+			result.set(i, m.columns+j, simplifyEntry(other.At(i, j)))
+		}
+	}
+	return result, nil
+}
+
+func (m *Matrix) slice(from int) *Matrix {
+	result := zeroMatrix(m.rows, m.columns-from)
+	for i := 0; i < result.rows; i++ {
+		for j := 0; j < result.columns; j++ {
+			result.set(i, j, m.At(i, from+j))
+		}
+	}
+	return result
+}
+
+// eliminate runs Gauss-Jordan elimination over the leading square block,
+// leaving the identity there and the solution in the remaining columns.
+func (m *Matrix) eliminate() error {
+	for k := 0; k < m.rows; k++ {
+		row := m.pivot(k, k)
+		if row < 0 {
+			return errors.New("matrix is singular")
+		}
+		m.swapRows(k, row)
+
+		pivot := m.At(k, k)
+		for j := k; j < m.columns; j++ {
+			m.set(k, j, simplifyEntry(Div(m.At(k, j), pivot)))
+		}
+		for i := 0; i < m.rows; i++ {
+			factor := m.At(i, k)
+			if i == k || isZero(factor) {
+				continue
+			}
+			for j := k; j < m.columns; j++ {
+				m.set(i, j, simplifyEntry(Sub(m.At(i, j), Mul(factor, m.At(k, j)))))
+			}
+		}
+	}
+	return nil
+}
+
+func (m *Matrix) Evaluate(vars map[string]float64) ([][]float64, error) {
+	result := make([][]float64, m.rows)
+	for i := range result {
+		result[i] = make([]float64, m.columns)
+		for j := range result[i] {
+			x, err := Evaluate(m.At(i, j), vars)
+			if err != nil {
+				return nil, err
+			}
+			result[i][j] = x
+		}
+	}
+	return result, nil
+}
+
+func LinearSystem(a, x, b *Matrix) ([]equation, error) {
+	if x.columns != 1 || b.columns != 1 {
+		return nil, errors.New("x and b must be column vectors")
+	}
+	product, err := a.Mul(x)
+	if err != nil {
+		return nil, err
+	}
+	if product.rows != b.rows {
+		return nil, fmt.Errorf("a %d-row system cannot equal a %d-row vector", product.rows, b.rows)
+	}
+
+	eqs := make([]equation, b.rows)
+	for i := range eqs {
+		eqs[i] = NewEquation(product.At(i, 0), b.At(i, 0))
+	}
+	return eqs, nil
+}
+
+func matrixNode(m **Matrix) pattern {
+	return func(v *value) bool {
+		if v.op != "matrix" {
+			return false
+		}
+		matrix, err := MatrixOf(*v)
+		*m = matrix
+		return err == nil
+	}
+}
+
+func scalar(val *value) pattern {
+	return func(v *value) bool {
+		return !containsMatrix(*v) && any(val)(v)
+	}
+}
+
+type matrixAddMatcher struct {
+	left, right *Matrix
+	operation   func(value, value) value
+}
+
+func (mm *matrixAddMatcher) Match(val *value) bool {
+	switch {
+	case bin(matrixNode(&mm.left), "+", matrixNode(&mm.right))(val):
+		mm.operation = Add
+	case bin(matrixNode(&mm.left), "-", matrixNode(&mm.right))(val):
+		mm.operation = Sub
+	default:
+		return false
+	}
+	return mm.left.rows == mm.right.rows && mm.left.columns == mm.right.columns
+}
+
+func (mm *matrixAddMatcher) Execute() value {
+	return mm.left.combine(mm.right, mm.operation).Value()
+}
+
+type matrixMulMatcher struct {
+	left, right *Matrix
+}
+
+func (mm *matrixMulMatcher) Match(val *value) bool {
+	return bin(matrixNode(&mm.left), "*", matrixNode(&mm.right))(val) && mm.left.columns == mm.right.rows
+}
+
+func (mm *matrixMulMatcher) Execute() value {
+	return mm.left.product(mm.right).Value()
+}
+
+type matrixScaleMatcher struct {
+	factor value
+	matrix *Matrix
+}
+
+func (mm *matrixScaleMatcher) Match(val *value) bool {
+	return bin(scalar(&mm.factor), "*", matrixNode(&mm.matrix))(val) ||
+		bin(matrixNode(&mm.matrix), "*", scalar(&mm.factor))(val)
+}
+
+func (mm *matrixScaleMatcher) Execute() value {
+	return mm.matrix.apply(func(entry value) value {
+		return Mul(mm.factor, entry)
+	}).Value()
+}
@@ -0,0 +1,269 @@
+package equations_test
+
+import (
+	"math"
+	"strings"
+	"testing"
+
+	"github.com/gossie/equations"
+)
+
+func symbolicMatrix(t *testing.T) *equations.Matrix {
+	t.Helper()
+	m, err := equations.NewMatrix(2, 2, equations.Var(1, "a", 1), equations.Var(1, "b", 1), equations.Var(1, "c", 1), equations.Var(1, "d", 1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func numericMatrix(t *testing.T) *equations.Matrix {
+	t.Helper()
+	m, err := equations.NewMatrix(3, 3,
+		equations.Num(2), equations.Num(1), equations.Num(0),
+		equations.Num(1), equations.Num(3), equations.Num(1),
+		equations.Num(0), equations.Num(1), equations.Num(4))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return m
+}
+
+func TestNewMatrix_wrongEntryCount(t *testing.T) {
+	if _, err := equations.NewMatrix(2, 2, equations.Num(1)); err == nil {
+		t.Fatal("expected an error for a missing entry")
+	}
+}
+
+func TestMatrix_transpose(t *testing.T) {
+	transposed := symbolicMatrix(t).Transpose()
+
+	expected := "[[1.000000a, 1.000000c], [1.000000b, 1.000000d]]"
+	if transposed.String() != expected {
+		t.Fatalf("expected %v to be %v", transposed, expected)
+	}
+}
+
+func TestMatrix_mul(t *testing.T) {
+	product, err := symbolicMatrix(t).Mul(equations.VariableVector("x", "y"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "[[((1.000000a * 1.000000x) + (1.000000b * 1.000000y))], [((1.000000c * 1.000000x) + (1.000000d * 1.000000y))]]"
+	if product.String() != expected {
+		t.Fatalf("expected %v to be %v", product, expected)
+	}
+
+	if _, err := equations.VariableVector("x", "y").Mul(equations.VariableVector("x", "y")); err == nil {
+		t.Fatal("expected an error for mismatched dimensions")
+	}
+}
+
+func TestMatrix_determinant(t *testing.T) {
+	det, err := symbolicMatrix(t).Determinant()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if det.String() != "((1.000000a * 1.000000d) + (-1.000000b * 1.000000c))" {
+		t.Fatalf("unexpected determinant %v", det)
+	}
+
+	det, _ = numericMatrix(t).Determinant()
+	if det.Number() != 18 {
+		t.Fatalf("expected %v to be 18", det)
+	}
+
+	if _, err := equations.VariableVector("x", "y").Determinant(); err == nil {
+		t.Fatal("expected an error for a non-square matrix")
+	}
+}
+
+func TestMatrix_inverse(t *testing.T) {
+	m := symbolicMatrix(t)
+	inverse, err := m.Inverse()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	product, _ := m.Mul(inverse)
+	if product.String() != equations.Identity(2).String() {
+		t.Fatalf("expected %v to be the identity", product)
+	}
+
+	singular, _ := equations.NewMatrix(2, 2, equations.Var(1, "a", 1), equations.Var(1, "b", 1), equations.Var(2, "a", 1), equations.Var(2, "b", 1))
+	if _, err := singular.Inverse(); err == nil {
+		t.Fatal("expected a singular matrix error")
+	}
+}
+
+func TestMatrix_solve(t *testing.T) {
+	x, err := numericMatrix(t).Solve(equations.Vector(equations.Num(3), equations.Num(5), equations.Num(5)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values, _ := x.Evaluate(nil)
+	for i, row := range values {
+		if math.Abs(row[0]-1) > 1e-12 {
+			t.Fatalf("expected x%d = %v to be 1", i, row[0])
+		}
+	}
+}
+
+func TestLinearSystem(t *testing.T) {
+	eqs, err := equations.LinearSystem(numericMatrix(t), equations.VariableVector("x", "y", "z"), equations.Vector(equations.Num(3), equations.Num(5), equations.Num(5)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(eqs) != 3 {
+		t.Fatalf("expected 3 equations, got %d", len(eqs))
+	}
+
+	expected := "(1.000000y + 4.000000z) = 5.000000"
+	if eqs[2].String() != expected {
+		t.Fatalf("expected %v to be %v", eqs[2], expected)
+	}
+
+	y, err := equations.SolveTo(&eqs[2], "y")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if y.String() != "(5.000000 + -4.000000z)" {
+		t.Fatalf("unexpected solution %v", y)
+	}
+}
+
+func TestMatrix_expressionTree(t *testing.T) {
+	m := symbolicMatrix(t).Value()
+
+	simplifier := equations.NewSimplifier()
+	substituted := simplifier.Simplify(equations.Substitute(m, equations.Replacements{"a": equations.Num(2), "d": equations.Num(3)}))
+	expected := "[[2.000000, 1.000000b], [1.000000c, 3.000000]]"
+	if substituted.String() != expected {
+		t.Fatalf("expected %v to be %v", substituted, expected)
+	}
+
+	scaled := simplifier.Simplify(equations.Mul(equations.Num(2), substituted))
+	expected = "[[4.000000, 2.000000b], [2.000000c, 6.000000]]"
+	if scaled.String() != expected {
+		t.Fatalf("expected %v to be %v", scaled, expected)
+	}
+
+	product := simplifier.Simplify(equations.Mul(m, equations.VariableVector("x", "y").Value()))
+	matrix, err := equations.MatrixOf(product)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if matrix.Rows() != 2 || matrix.Columns() != 1 {
+		t.Fatalf("expected a 2x1 matrix, got %v", matrix)
+	}
+
+	difference := simplifier.Simplify(equations.Sub(m, m))
+	expected = "[[0.000000, 0.000000], [0.000000, 0.000000]]"
+	if difference.String() != expected {
+		t.Fatalf("expected %v to be %v", difference, expected)
+	}
+
+	derivative, _ := equations.Derive(equations.VariableVector("x", "y").Value(), "x")
+	expected = "[[1.000000], [0.000000]]"
+	if derivative.String() != expected {
+		t.Fatalf("expected %v to be %v", derivative, expected)
+	}
+
+	if _, err := equations.MatrixOf(equations.Num(1)); err == nil {
+		t.Fatal("expected an error for a scalar")
+	}
+}
+
+func TestMatrix_determinantPivots(t *testing.T) {
+	n := equations.Num
+	m, err := equations.NewMatrix(5, 5,
+		n(0), n(0), n(0), n(0), n(1),
+		n(0), n(0), n(0), n(2), n(0),
+		n(0), n(0), n(3), n(0), n(0),
+		n(0), n(4), n(0), n(0), n(0),
+		n(5), n(0), n(0), n(0), n(0))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	det, err := m.Determinant()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if det.Number() != 120 {
+		t.Fatalf("expected %v to be 120", det)
+	}
+}
+
+func TestMatrix_determinantSymbolic3x3(t *testing.T) {
+	v := equations.Var
+	m, _ := equations.NewMatrix(3, 3,
+		v(1, "a", 1), v(1, "b", 1), v(1, "c", 1),
+		v(1, "d", 1), v(1, "e", 1), v(1, "f", 1),
+		v(1, "g", 1), v(1, "h", 1), v(1, "i", 1))
+
+	det, err := m.Determinant()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	vars := map[string]float64{"a": 2, "b": 1, "c": 0, "d": 1, "e": 3, "f": 1, "g": 0, "h": 1, "i": 4}
+	result, err := equations.Evaluate(det, vars)
+	if err != nil {
+		t.Fatalf("expected %v to be a polynomial: %v", det, err)
+	}
+	if math.Abs(result-18) > 1e-12 {
+		t.Fatalf("expected %v to evaluate to 18, got %v", det, result)
+	}
+}
+
+func TestMatrix_solveToReportsMatrix(t *testing.T) {
+	a := symbolicMatrix(t).Value()
+	b := equations.Vector(equations.Num(1), equations.Num(2)).Value()
+
+	eq := equations.NewEquation(equations.Mul(a, equations.Var(1, "x", 1)), b)
+	if _, err := equations.SolveTo(&eq, "x"); err == nil {
+		t.Fatal("expected an error for a variable multiplied by a matrix")
+	}
+
+	eq = equations.NewEquation(equations.Mul(a, equations.VariableVector("x", "y").Value()), b)
+	if _, err := equations.SolveSetTo(&eq, "x"); err == nil {
+		t.Fatal("expected an error for a variable inside a matrix")
+	}
+}
+
+func TestMatrix_render(t *testing.T) {
+	m, _ := equations.NewMatrix(2, 2, equations.Num(1), equations.Var(1, "a", 1), equations.Num(10), equations.Num(3))
+
+	expected := "[1   a]\n[10  3]"
+	if rendered := equations.Render(m.Value(), equations.ASCII); rendered != expected {
+		t.Fatalf("expected\n%v\nto be\n%v", rendered, expected)
+	}
+
+	expected = "⎡1   a⎤\n⎣10  3⎦"
+	if rendered := equations.Render(m.Value(), equations.Unicode); rendered != expected {
+		t.Fatalf("expected\n%v\nto be\n%v", rendered, expected)
+	}
+
+	dot := equations.Dot(m.Value())
+	if strings.Contains(dot, "\"arg\"") || !strings.Contains(dot, "\"entry\"") {
+		t.Fatalf("expected matrix cells to be labelled as entries in\n%v", dot)
+	}
+}
+
+func TestMatrix_solvePivotsOnLargestEntry(t *testing.T) {
+	m, _ := equations.NewMatrix(2, 2, equations.Num(1e-20), equations.Num(1), equations.Num(1), equations.Num(1))
+	x, err := m.Solve(equations.Vector(equations.Num(1), equations.Num(2)))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	values, _ := x.Evaluate(nil)
+	for i, row := range values {
+		if math.Abs(row[0]-1) > 1e-12 {
+			t.Fatalf("expected x%d = %v to be 1", i, row[0])
+		}
+	}
+}
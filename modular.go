@@ -0,0 +1,267 @@
+package equations
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+)
+
+type Congruence struct {
+	eq      equation
+	Modulus int64
+}
+
+func NewCongruence(left, right value, modulus int64) Congruence {
+	return Congruence{NewEquation(left, right), modulus}
+}
+
+func (c Congruence) String() string {
+	return fmt.Sprintf("%v ≡ %v (mod %d)", c.eq.left, c.eq.right, c.Modulus)
+}
+
+func extendedGCD(a, b int64) (int64, int64, int64) {
+	oldR, r := a, b
+	oldS, s := int64(1), int64(0)
+	oldT, t := int64(0), int64(1)
+	for r != 0 {
+		q := oldR / r
+		oldR, r = r, oldR-q*r
+		oldS, s = s, oldS-q*s
+		oldT, t = t, oldT-q*t
+	}
+	if oldR < 0 {
+		return -oldR, -oldS, -oldT
+	}
+	return oldR, oldS, oldT
+}
+
+func mod(a, m int64) int64 {
+	r := a % m
+	if r < 0 {
+		r += m
+	}
+	return r
+}
+
+func mulMod(a, b, m int64) int64 {
+	product := new(big.Int).Mul(big.NewInt(a), big.NewInt(b))
+	return product.Mod(product, big.NewInt(m)).Int64()
+}
+
+func modInverse(a, m int64) (int64, error) {
+	g, x, _ := extendedGCD(mod(a, m), m)
+	if g != 1 {
+		return 0, fmt.Errorf("%d has no inverse modulo %d", a, m)
+	}
+	return mod(x, m), nil
+}
+
+func integerCoefficients(eq *equation, scaled bool) (map[string]int64, int64, error) {
+	p, err := toPolynomial(Sub(eq.left, eq.right))
+	if err != nil {
+		return nil, 0, err
+	}
+
+	denominators := big.NewInt(1)
+	for _, t := range p.terms {
+		if t.monomial.degree() > 1 {
+			return nil, 0, errors.New(eq.String() + " is not linear")
+		}
+		denominators = lcm(denominators, t.coefficient.Denom())
+	}
+	if denominators.Cmp(big.NewInt(1)) != 0 && !scaled {
+		return nil, 0, errors.New(eq.String() + " has non-integer coefficients")
+	}
+
+	coefficients := make(map[string]int64)
+	var constant int64
+	for _, t := range p.terms {
+		c := new(big.Rat).Mul(t.coefficient, new(big.Rat).SetInt(denominators))
+		if !c.Num().IsInt64() {
+			return nil, 0, errors.New(eq.String() + " has coefficients that are too large")
+		}
+		if len(t.monomial) == 0 {
+			constant = -c.Num().Int64()
+			continue
+		}
+		for name := range t.monomial {
+			coefficients[name] = c.Num().Int64()
+		}
+	}
+	return coefficients, constant, nil
+}
+
+func residue(c Congruence, varName string) (int64, int64, bool, error) {
+	if c.Modulus <= 0 {
+		return 0, 0, false, fmt.Errorf("modulus of %v must be positive", c)
+	}
+	coefficients, constant, err := integerCoefficients(&c.eq, false)
+	if err != nil {
+		return 0, 0, false, err
+	}
+	for name := range coefficients {
+		if name != varName {
+			return 0, 0, false, errors.New(c.String() + " contains other variables than " + varName)
+		}
+	}
+
+	a, m := mod(coefficients[varName], c.Modulus), c.Modulus
+	b := mod(constant, m)
+	g, _, _ := extendedGCD(a, m)
+	if b%g != 0 {
+		return 0, 0, false, nil
+	}
+	if a == 0 {
+		return 0, 1, true, nil
+	}
+	inverse, _ := modInverse(a/g, m/g)
+	return mulMod(b/g, inverse, m/g), m / g, true, nil
+}
+
+func congruenceFamily(r, m int64, parameter string) *SolutionSet {
+	if m == 1 {
+		return &SolutionSet{Kind: AllIntegers}
+	}
+	family := Add(Var(float64(m), parameter, 1), Num(float64(r))).execute()
+	return &SolutionSet{Kind: ParametricFamily, Values: []value{family}, Parameters: []string{parameter}}
+}
+
+func SolveCongruence(c Congruence, varName string) (*SolutionSet, error) {
+	r, m, solvable, err := residue(c, varName)
+	if err != nil {
+		return nil, err
+	}
+	if !solvable {
+		return &SolutionSet{Kind: EmptySet}, nil
+	}
+	return congruenceFamily(r, m, freshNames("k", 1, []string{varName})[0]), nil
+}
+
+func SolveCongruences(varName string, cs ...Congruence) (*SolutionSet, error) {
+	if len(cs) == 0 {
+		return nil, errors.New("no congruences given")
+	}
+
+	r, m := int64(0), int64(1)
+	for _, c := range cs {
+		ri, mi, solvable, err := residue(c, varName)
+		if err != nil {
+			return nil, err
+		}
+		if !solvable {
+			return &SolutionSet{Kind: EmptySet}, nil
+		}
+
+		g, _, _ := extendedGCD(m, mi)
+		if (ri-r)%g != 0 {
+			return &SolutionSet{Kind: EmptySet}, nil
+		}
+		lcm := new(big.Int).Mul(big.NewInt(m/g), big.NewInt(mi))
+		if !lcm.IsInt64() {
+			return nil, fmt.Errorf("the combined modulus of %v overflows", cs)
+		}
+		inverse, _ := modInverse(m/g, mi/g)
+		step := mulMod(mod((ri-r)/g, mi/g), inverse, mi/g)
+		r, m = r+m*step, lcm.Int64()
+	}
+	return congruenceFamily(r, m, freshNames("k", 1, []string{varName})[0]), nil
+}
+
+func freshNames(base string, count int, taken []string) []string {
+	used := make(map[string]bool, len(taken))
+	for _, name := range taken {
+		used[name] = true
+	}
+	if count == 1 && !used[base] {
+		return []string{base}
+	}
+
+	names := make([]string, 0, count)
+	for k := 1; len(names) < count; k++ {
+		if name := fmt.Sprintf("%v%d", base, k); !used[name] {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+func SolveDiophantine(eq *equation) (*SolutionSet, error) {
+	coefficients, constant, err := integerCoefficients(eq, true)
+	if err != nil {
+		return nil, err
+	}
+
+	vars := newPolynomial()
+	for name := range coefficients {
+		vars.addTerm(big.NewRat(1, 1), monomial{name: 1})
+	}
+	names := vars.variables()
+	n := len(names)
+	if n == 0 {
+		if constant == 0 {
+			return &SolutionSet{Kind: AllIntegers}, nil
+		}
+		return &SolutionSet{Kind: EmptySet}, nil
+	}
+
+	a := make([]*big.Int, n)
+	u := make([][]*big.Int, n)
+	for i, name := range names {
+		a[i] = big.NewInt(coefficients[name])
+		u[i] = make([]*big.Int, n)
+		for j := range u[i] {
+			u[i][j] = new(big.Int)
+		}
+		u[i][i].SetInt64(1)
+	}
+	for j := 1; j < n; j++ {
+		s, t := new(big.Int), new(big.Int)
+		g := new(big.Int).GCD(s, t, a[0], a[j])
+		p, q := new(big.Int).Quo(a[j], g), new(big.Int).Quo(a[0], g)
+		for i := range u {
+			first := new(big.Int).Add(new(big.Int).Mul(s, u[i][0]), new(big.Int).Mul(t, u[i][j]))
+			second := new(big.Int).Sub(new(big.Int).Mul(q, u[i][j]), new(big.Int).Mul(p, u[i][0]))
+			u[i][0], u[i][j] = first, second
+		}
+		a[0], a[j] = g, new(big.Int)
+	}
+	if a[0].Sign() < 0 {
+		a[0].Neg(a[0])
+		for i := range u {
+			u[i][0].Neg(u[i][0])
+		}
+	}
+	c := big.NewInt(constant)
+	if new(big.Int).Rem(c, a[0]).Sign() != 0 {
+		return &SolutionSet{Kind: EmptySet}, nil
+	}
+	particular := new(big.Int).Quo(c, a[0])
+
+	if n == 1 {
+		return &SolutionSet{Kind: FiniteSet, Variables: names, Values: []value{Num(float64(new(big.Int).Mul(particular, u[0][0]).Int64()))}}, nil
+	}
+	parameters := freshNames("t", n-1, names)
+
+	offsets := make([]*big.Int, n)
+	for i := range u {
+		offsets[i] = new(big.Int).Mul(particular, u[i][0])
+	}
+	if n == 2 && u[0][1].Sign() != 0 {
+		step := new(big.Int).Abs(u[0][1])
+		shift := new(big.Int).Sub(new(big.Int).Mod(offsets[0], step), offsets[0])
+		shift.Quo(shift, u[0][1])
+		for i := range u {
+			offsets[i].Add(offsets[i], new(big.Int).Mul(shift, u[i][1]))
+		}
+	}
+
+	values := make([]value, n)
+	for i := range u {
+		p := constantPolynomial(new(big.Rat).SetInt(offsets[i]))
+		for j, parameter := range parameters {
+			p = p.add(monomialPolynomial(new(big.Rat).SetInt(u[i][j+1]), parameter, 1))
+		}
+		values[i] = fromPolynomial(p)
+	}
+	return &SolutionSet{Kind: ParametricFamily, Variables: names, Values: values, Parameters: parameters}, nil
+}
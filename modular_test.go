@@ -0,0 +1,187 @@
+package equations_test
+
+import (
+	"testing"
+
+	"github.com/gossie/equations"
+)
+
+func TestSolveCongruence(t *testing.T) {
+	c := equations.NewCongruence(equations.Var(3, "x", 1), equations.Num(4), 7)
+	if c.String() != "3.000000x ≡ 4.000000 (mod 7)" {
+		t.Fatalf("expected %v to be 3.000000x ≡ 4.000000 (mod 7)", c)
+	}
+
+	solutions, err := equations.SolveCongruence(c, "x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if solutions.String() != "{(7.000000k + 6.000000) : k}" {
+		t.Fatalf("expected %v to be {(7.000000k + 6.000000) : k}", solutions)
+	}
+}
+
+func TestSolveCongruence_notCoprime(t *testing.T) {
+	solvable := equations.NewCongruence(equations.Var(4, "x", 1), equations.Num(6), 10)
+	solutions, _ := equations.SolveCongruence(solvable, "x")
+	if solutions.String() != "{(5.000000k + 4.000000) : k}" {
+		t.Fatalf("expected %v to be {(5.000000k + 4.000000) : k}", solutions)
+	}
+
+	unsolvable := equations.NewCongruence(equations.Var(4, "x", 1), equations.Num(5), 10)
+	solutions, _ = equations.SolveCongruence(unsolvable, "x")
+	if solutions.Kind != equations.EmptySet {
+		t.Fatalf("expected %v to be empty", solutions)
+	}
+}
+
+func TestSolveCongruence_everyInteger(t *testing.T) {
+	c := equations.NewCongruence(equations.Var(7, "x", 1), equations.Num(0), 7)
+
+	solutions, err := equations.SolveCongruence(c, "x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if solutions.Kind != equations.AllIntegers || solutions.String() != "Z" {
+		t.Fatalf("expected %v to be Z", solutions)
+	}
+}
+
+func TestSolveCongruence_largeModulus(t *testing.T) {
+	c := equations.NewCongruence(equations.Var(3, "x", 1), equations.Num(10000000000), 10000000019)
+
+	solutions, err := equations.SolveCongruence(c, "x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if solutions.String() != "{(10000000019.000000k + 6666666673.000000) : k}" {
+		t.Fatalf("expected %v to be {(10000000019.000000k + 6666666673.000000) : k}", solutions)
+	}
+}
+
+func TestSolveCongruences(t *testing.T) {
+	solutions, err := equations.SolveCongruences("x",
+		equations.NewCongruence(equations.Var(1, "x", 1), equations.Num(2), 3),
+		equations.NewCongruence(equations.Var(1, "x", 1), equations.Num(3), 5),
+		equations.NewCongruence(equations.Var(1, "x", 1), equations.Num(2), 7),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if solutions.String() != "{(105.000000k + 23.000000) : k}" {
+		t.Fatalf("expected %v to be {(105.000000k + 23.000000) : k}", solutions)
+	}
+
+	conflicting, _ := equations.SolveCongruences("x",
+		equations.NewCongruence(equations.Var(1, "x", 1), equations.Num(1), 4),
+		equations.NewCongruence(equations.Var(1, "x", 1), equations.Num(2), 6),
+	)
+	if conflicting.Kind != equations.EmptySet {
+		t.Fatalf("expected %v to be empty", conflicting)
+	}
+}
+
+func TestSolveCongruences_overflowingModulus(t *testing.T) {
+	_, err := equations.SolveCongruences("x",
+		equations.NewCongruence(equations.Var(1, "x", 1), equations.Num(1), 4000000007),
+		equations.NewCongruence(equations.Var(1, "x", 1), equations.Num(2), 3000000019),
+	)
+	if err == nil {
+		t.Fatal("expected the combined modulus to overflow")
+	}
+}
+
+func TestSolveDiophantine(t *testing.T) {
+	eq := equations.NewEquation(equations.Add(equations.Var(6, "x", 1), equations.Var(9, "y", 1)), equations.Num(15))
+
+	solutions, err := equations.SolveDiophantine(&eq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if solutions.String() != "{x = (-3.000000t + 1.000000), y = (2.000000t + 1.000000) : t}" {
+		t.Fatalf("expected %v to be {x = (-3.000000t + 1.000000), y = (2.000000t + 1.000000) : t}", solutions)
+	}
+}
+
+func TestSolveDiophantine_largeCoefficients(t *testing.T) {
+	eq := equations.NewEquation(equations.Add(equations.Var(1000000007, "x", 1), equations.Var(998244353, "y", 1)), equations.Num(1e12))
+
+	solutions, err := equations.SolveDiophantine(&eq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	x, _ := equations.Evaluate(solutions.Values[0], map[string]float64{"t": 0})
+	y, _ := equations.Evaluate(solutions.Values[1], map[string]float64{"t": 0})
+	if x != 467814998 || y != -468636762 {
+		t.Fatalf("expected x=467814998, y=-468636762 in %v", solutions)
+	}
+}
+
+func TestSolveDiophantine_identity(t *testing.T) {
+	eq := equations.NewEquation(equations.Num(3), equations.Num(3))
+
+	solutions, _ := equations.SolveDiophantine(&eq)
+	if solutions.Kind != equations.AllIntegers {
+		t.Fatalf("expected %v to be Z", solutions)
+	}
+}
+
+func TestSolveDiophantine_noSolution(t *testing.T) {
+	eq := equations.NewEquation(equations.Add(equations.Var(6, "x", 1), equations.Var(9, "y", 1)), equations.Num(10))
+
+	solutions, _ := equations.SolveDiophantine(&eq)
+	if solutions.Kind != equations.EmptySet {
+		t.Fatalf("expected %v to be empty", solutions)
+	}
+}
+
+func TestSolveDiophantine_threeVariables(t *testing.T) {
+	eq := equations.NewEquation(equations.Add(equations.Add(equations.Var(6, "x", 1), equations.Var(10, "y", 1)), equations.Var(15, "z", 1)), equations.Num(7))
+
+	solutions, err := equations.SolveDiophantine(&eq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(solutions.Parameters) != 2 {
+		t.Fatalf("expected two parameters in %v", solutions)
+	}
+
+	for _, parameters := range []map[string]float64{{"t1": 0, "t2": 0}, {"t1": 3, "t2": -2}, {"t1": -5, "t2": 7}} {
+		x, _ := equations.Evaluate(solutions.Values[0], parameters)
+		y, _ := equations.Evaluate(solutions.Values[1], parameters)
+		z, _ := equations.Evaluate(solutions.Values[2], parameters)
+		if 6*x+10*y+15*z != 7 {
+			t.Fatalf("expected x=%v, y=%v, z=%v to solve %v", x, y, z, eq)
+		}
+	}
+}
+
+func TestSolveDiophantine_freshParameter(t *testing.T) {
+	eq := equations.NewEquation(equations.Add(equations.Var(1, "x", 1), equations.Var(1, "t", 1)), equations.Num(5))
+
+	solutions, err := equations.SolveDiophantine(&eq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "{t = -1.000000t1, x = (1.000000t1 + 5.000000) : t1}"
+	if solutions.String() != expected {
+		t.Fatalf("expected %v to be %v", solutions, expected)
+	}
+}
+
+func TestSolveDiophantine_singleVariable(t *testing.T) {
+	eq := equations.NewEquation(equations.Var(3, "x", 1), equations.Num(9))
+	solutions, err := equations.SolveDiophantine(&eq)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if solutions.Kind != equations.FiniteSet || solutions.String() != "{x = 3.000000}" {
+		t.Fatalf("expected %v to be {x = 3.000000}", solutions)
+	}
+
+	eq = equations.NewEquation(equations.Var(-3, "x", 1), equations.Num(6))
+	solutions, _ = equations.SolveDiophantine(&eq)
+	if solutions.String() != "{x = -2.000000}" {
+		t.Fatalf("expected %v to be {x = -2.000000}", solutions)
+	}
+}
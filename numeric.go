@@ -0,0 +1,299 @@
+package equations
+
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+type NumericMethod int
+
+const (
+	Newton NumericMethod = iota
+	Bisection
+	Brent
+	Scan
+)
+
+type NumericOptions struct {
+	Method        NumericMethod
+	Initial       float64
+	Lower, Upper  float64
+	Tolerance     float64
+	MaxIterations int
+	Steps         int
+}
+
+type NumericResult struct {
+	Roots      []float64
+	Iterations int
+	Residual   float64
+	Converged  bool
+	Reason     string
+}
+
+func (nr *NumericResult) String() string {
+	if nr.Converged {
+		return fmt.Sprintf("%v after %d iterations (residual %g)", nr.Roots, nr.Iterations, nr.Residual)
+	}
+	return fmt.Sprintf("not converged after %d iterations: %v", nr.Iterations, nr.Reason)
+}
+
+func (opts NumericOptions) withDefaults() NumericOptions {
+	if opts.Tolerance <= 0 {
+		opts.Tolerance = 1e-12
+	}
+	if opts.MaxIterations <= 0 {
+		opts.MaxIterations = 100
+	}
+	if opts.Steps <= 0 {
+		opts.Steps = 100
+	}
+	return opts
+}
+
+type realFunction func(float64) (float64, error)
+
+func residualFunction(f value, varName string) realFunction {
+	return func(x float64) (float64, error) {
+		return Evaluate(f, map[string]float64{varName: x})
+	}
+}
+
+func SolveNumeric(eq *equation, varName string, opts NumericOptions) (*NumericResult, error) {
+	opts = opts.withDefaults()
+	residual := Sub(eq.left, eq.right).execute()
+	f := residualFunction(residual, varName)
+
+	switch opts.Method {
+	default:
+		return nil, fmt.Errorf("unknown numeric method %d", opts.Method)
+	case Newton:
+		derivative, err := Derive(residual, varName)
+		if err != nil {
+			return nil, err
+		}
+		return newton(f, residualFunction(derivative, varName), opts)
+	case Bisection:
+		return bisection(f, opts.Lower, opts.Upper, opts)
+	case Brent:
+		return brent(f, opts.Lower, opts.Upper, opts)
+	case Scan:
+		return scan(f, opts)
+	}
+}
+
+func newton(f, df realFunction, opts NumericOptions) (*NumericResult, error) {
+	x := opts.Initial
+	for i := 1; i <= opts.MaxIterations; i++ {
+		fx, err := f(x)
+		if err != nil {
+			return nil, err
+		}
+		if math.IsNaN(fx) || math.IsInf(fx, 0) {
+			return &NumericResult{Iterations: i, Residual: fx, Reason: fmt.Sprintf("residual is not finite at %v", x)}, nil
+		}
+		if math.Abs(fx) <= opts.Tolerance {
+			return &NumericResult{Roots: []float64{x}, Iterations: i, Residual: math.Abs(fx), Converged: true}, nil
+		}
+
+		dfx, err := df(x)
+		if err != nil {
+			return nil, err
+		}
+		if dfx == 0 || math.IsNaN(dfx) {
+			return &NumericResult{Iterations: i, Residual: math.Abs(fx), Reason: fmt.Sprintf("derivative vanishes at %v", x)}, nil
+		}
+
+		step := fx / dfx
+		x -= step
+		if math.Abs(step) <= opts.Tolerance*(1+math.Abs(x)) {
+			fx, err = f(x)
+			if err != nil {
+				return nil, err
+			}
+			return &NumericResult{Roots: []float64{x}, Iterations: i, Residual: math.Abs(fx), Converged: true}, nil
+		}
+	}
+	fx, _ := f(x)
+	return &NumericResult{Iterations: opts.MaxIterations, Residual: math.Abs(fx), Reason: "iteration limit reached"}, nil
+}
+
+func bracket(f realFunction, a, b float64) (float64, float64, error) {
+	if !(a < b) {
+		return 0, 0, fmt.Errorf("invalid bracket [%v, %v]", a, b)
+	}
+	fa, err := f(a)
+	if err != nil {
+		return 0, 0, err
+	}
+	fb, err := f(b)
+	if err != nil {
+		return 0, 0, err
+	}
+	if fa*fb > 0 {
+		return 0, 0, fmt.Errorf("residual does not change sign on [%v, %v]", a, b)
+	}
+	return fa, fb, nil
+}
+
+const machineEpsilon = 0x1p-52
+
+func (opts NumericOptions) tolerance(x float64) float64 {
+	return 2*machineEpsilon*math.Abs(x) + 0.5*opts.Tolerance
+}
+
+func bisection(f realFunction, a, b float64, opts NumericOptions) (*NumericResult, error) {
+	fa, fb, err := bracket(f, a, b)
+	if err != nil {
+		return nil, err
+	}
+	if fa == 0 {
+		return &NumericResult{Roots: []float64{a}, Converged: true}, nil
+	}
+	if fb == 0 {
+		return &NumericResult{Roots: []float64{b}, Converged: true}, nil
+	}
+
+	for i := 1; i <= opts.MaxIterations; i++ {
+		m := a + (b-a)/2
+		fm, err := f(m)
+		if err != nil {
+			return nil, err
+		}
+		if fm == 0 || (b-a)/2 <= opts.tolerance(m) {
+			return &NumericResult{Roots: []float64{m}, Iterations: i, Residual: math.Abs(fm), Converged: true}, nil
+		}
+		if fa*fm < 0 {
+			b = m
+		} else {
+			a, fa = m, fm
+		}
+	}
+	return &NumericResult{Iterations: opts.MaxIterations, Residual: math.Abs(fa), Reason: "iteration limit reached"}, nil
+}
+
+func brent(f realFunction, a, b float64, opts NumericOptions) (*NumericResult, error) {
+	fa, fb, err := bracket(f, a, b)
+	if err != nil {
+		return nil, err
+	}
+
+	c, fc := b, fb
+	var d, e float64
+	for i := 1; i <= opts.MaxIterations; i++ {
+		if fb*fc > 0 {
+			c, fc = a, fa
+			d = b - a
+			e = d
+		}
+		if math.Abs(fc) < math.Abs(fb) {
+			a, b, c = b, c, b
+			fa, fb, fc = fb, fc, fb
+		}
+
+		tol := opts.tolerance(b)
+		m := 0.5 * (c - b)
+		if math.Abs(m) <= tol || fb == 0 {
+			return &NumericResult{Roots: []float64{b}, Iterations: i, Residual: math.Abs(fb), Converged: true}, nil
+		}
+
+		if math.Abs(e) >= tol && math.Abs(fa) > math.Abs(fb) {
+			var p, q float64
+			s := fb / fa
+			if a == c {
+				p = 2 * m * s
+				q = 1 - s
+			} else {
+				q = fa / fc
+				r := fb / fc
+				p = s * (2*m*q*(q-r) - (b-a)*(r-1))
+				q = (q - 1) * (r - 1) * (s - 1)
+			}
+			if p > 0 {
+				q = -q
+			}
+			p = math.Abs(p)
+			if 2*p < math.Min(3*m*q-math.Abs(tol*q), math.Abs(e*q)) {
+				e = d
+				d = p / q
+			} else {
+				d = m
+				e = d
+			}
+		} else {
+			d = m
+			e = d
+		}
+
+		a, fa = b, fb
+		if math.Abs(d) > tol {
+			b += d
+		} else {
+			b += math.Copysign(tol, m)
+		}
+		fb, err = f(b)
+		if err != nil {
+			return nil, err
+		}
+	}
+	return &NumericResult{Iterations: opts.MaxIterations, Residual: math.Abs(fb), Reason: "iteration limit reached"}, nil
+}
+
+func scan(f realFunction, opts NumericOptions) (*NumericResult, error) {
+	if !(opts.Lower < opts.Upper) {
+		return nil, fmt.Errorf("invalid interval [%v, %v]", opts.Lower, opts.Upper)
+	}
+
+	result := &NumericResult{Roots: make([]float64, 0), Converged: true}
+	width := (opts.Upper - opts.Lower) / float64(opts.Steps)
+	a := opts.Lower
+	fa, err := f(a)
+	if err != nil {
+		return nil, err
+	}
+	for i := 1; i <= opts.Steps; i++ {
+		b := opts.Lower + float64(i)*width
+		fb, err := f(b)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case fa == 0:
+			result.addRoot(a, 0, opts.Tolerance)
+		case fa*fb < 0:
+			sub, err := brent(f, a, b, opts)
+			if err != nil {
+				return nil, err
+			}
+			result.Iterations += sub.Iterations
+			if sub.Converged {
+				result.addRoot(sub.Roots[0], sub.Residual, opts.Tolerance)
+			} else {
+				result.Converged = false
+				result.Reason = fmt.Sprintf("no convergence on [%v, %v]: %v", a, b, sub.Reason)
+			}
+		}
+		a, fa = b, fb
+	}
+	if fa == 0 {
+		result.addRoot(a, 0, opts.Tolerance)
+	}
+
+	sort.Float64s(result.Roots)
+	if len(result.Roots) == 0 && result.Converged {
+		result.Reason = "no sign change found"
+	}
+	return result, nil
+}
+
+func (nr *NumericResult) addRoot(root, residual, tolerance float64) {
+	for _, r := range nr.Roots {
+		if math.Abs(r-root) <= tolerance*(1+math.Abs(root)) {
+			return
+		}
+	}
+	nr.Roots = append(nr.Roots, root)
+	nr.Residual = math.Max(nr.Residual, residual)
+}
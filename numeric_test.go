@@ -0,0 +1,98 @@
+package equations_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gossie/equations"
+)
+
+func TestSolveNumeric_newton(t *testing.T) {
+	eq := equations.NewEquation(equations.Var(1, "x", 2), equations.Num(2))
+
+	result, err := equations.SolveNumeric(&eq, "x", equations.NumericOptions{Method: equations.Newton, Initial: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Converged || math.Abs(result.Roots[0]-math.Sqrt2) > 1e-12 {
+		t.Fatalf("expected %v to be %v", result, math.Sqrt2)
+	}
+}
+
+func TestSolveNumeric_newtonDerivativeVanishes(t *testing.T) {
+	eq := equations.NewEquation(equations.Var(1, "x", 2), equations.Num(2))
+
+	result, err := equations.SolveNumeric(&eq, "x", equations.NumericOptions{Method: equations.Newton, Initial: 0})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Converged || result.Reason != "derivative vanishes at 0" {
+		t.Fatalf("expected %v to fail because the derivative vanishes", result)
+	}
+}
+
+func TestSolveNumeric_brent(t *testing.T) {
+	eq := equations.NewEquation(equations.Cos(equations.Var(1, "x", 1)), equations.Var(1, "x", 1))
+
+	result, err := equations.SolveNumeric(&eq, "x", equations.NumericOptions{Method: equations.Brent, Lower: 0, Upper: 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Converged || math.Abs(result.Roots[0]-0.7390851332151607) > 1e-10 {
+		t.Fatalf("expected %v to be 0.7390851332151607", result)
+	}
+}
+
+func TestSolveNumeric_bisection(t *testing.T) {
+	eq := equations.NewEquation(equations.Exp(equations.Var(1, "x", 1)), equations.Num(2))
+
+	result, err := equations.SolveNumeric(&eq, "x", equations.NumericOptions{Method: equations.Bisection, Lower: 0, Upper: 1, Tolerance: 1e-10})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Converged || math.Abs(result.Roots[0]-math.Ln2) > 1e-9 {
+		t.Fatalf("expected %v to be %v", result, math.Ln2)
+	}
+}
+
+func TestSolveNumeric_bracketWithoutSignChange(t *testing.T) {
+	eq := equations.NewEquation(equations.Var(1, "x", 2), equations.Num(-1))
+
+	_, err := equations.SolveNumeric(&eq, "x", equations.NumericOptions{Method: equations.Brent, Lower: -1, Upper: 1})
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestSolveNumeric_scan(t *testing.T) {
+	eq := equations.NewEquation(equations.Sin(equations.Var(1, "x", 1)), equations.Num(0))
+
+	result, err := equations.SolveNumeric(&eq, "x", equations.NumericOptions{Method: equations.Scan, Lower: -1, Upper: 7, Steps: 80})
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := []float64{0, math.Pi, 2 * math.Pi}
+	if len(result.Roots) != len(expected) {
+		t.Fatalf("expected %v to be %v", result.Roots, expected)
+	}
+	for i := range expected {
+		if math.Abs(result.Roots[i]-expected[i]) > 1e-10 {
+			t.Fatalf("expected %v to be %v", result.Roots, expected)
+		}
+	}
+}
+
+func TestSolveNumeric_largeMagnitudeRoot(t *testing.T) {
+	eq := equations.NewEquation(equations.Var(1, "x", 2), equations.Num(2e12))
+	expected := math.Sqrt(2e12)
+
+	for _, method := range []equations.NumericMethod{equations.Brent, equations.Bisection, equations.Scan} {
+		result, err := equations.SolveNumeric(&eq, "x", equations.NumericOptions{Method: method, Lower: 1e6, Upper: 2e6})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !result.Converged || len(result.Roots) != 1 || math.Abs(result.Roots[0]-expected) > 1e-9*expected {
+			t.Fatalf("expected method %d to find %v, got %v", method, expected, result)
+		}
+	}
+}
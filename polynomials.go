@@ -0,0 +1,323 @@
+package equations
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+type monomial map[string]int
+
+func (m monomial) key() string {
+	names := make([]string, 0, len(m))
+	for name := range m {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	parts := make([]string, 0, len(names))
+	for _, name := range names {
+		parts = append(parts, fmt.Sprintf("%v^%d", name, m[name]))
+	}
+	return strings.Join(parts, " ")
+}
+
+func (m monomial) degree() int {
+	degree := 0
+	for _, e := range m {
+		degree += e
+	}
+	return degree
+}
+
+func (m monomial) times(other monomial) monomial {
+	result := make(monomial, len(m)+len(other))
+	for name, e := range m {
+		result[name] = e
+	}
+	for name, e := range other {
+		result[name] += e
+	}
+	return result
+}
+
+type polyTerm struct {
+	coefficient *big.Rat
+	monomial    monomial
+}
+
+type polynomial struct {
+	terms map[string]polyTerm
+}
+
+func newPolynomial() polynomial {
+	return polynomial{terms: make(map[string]polyTerm)}
+}
+
+func constantPolynomial(c *big.Rat) polynomial {
+	p := newPolynomial()
+	p.addTerm(c, monomial{})
+	return p
+}
+
+func monomialPolynomial(c *big.Rat, name string, exponent int) polynomial {
+	p := newPolynomial()
+	if exponent == 0 {
+		p.addTerm(c, monomial{})
+	} else {
+		p.addTerm(c, monomial{name: exponent})
+	}
+	return p
+}
+
+func (p polynomial) addTerm(c *big.Rat, m monomial) {
+	key := m.key()
+	if existing, present := p.terms[key]; present {
+		sum := new(big.Rat).Add(existing.coefficient, c)
+		if sum.Sign() == 0 {
+			delete(p.terms, key)
+			return
+		}
+		p.terms[key] = polyTerm{sum, existing.monomial}
+		return
+	}
+	if c.Sign() != 0 {
+		p.terms[key] = polyTerm{new(big.Rat).Set(c), m}
+	}
+}
+
+func (p polynomial) isZero() bool {
+	return len(p.terms) == 0
+}
+
+func (p polynomial) constant() (*big.Rat, bool) {
+	if p.isZero() {
+		return new(big.Rat), true
+	}
+	if t, present := p.terms[""]; present && len(p.terms) == 1 {
+		return t.coefficient, true
+	}
+	return nil, false
+}
+
+func (p polynomial) add(q polynomial) polynomial {
+	result := newPolynomial()
+	for _, t := range p.terms {
+		result.addTerm(t.coefficient, t.monomial)
+	}
+	for _, t := range q.terms {
+		result.addTerm(t.coefficient, t.monomial)
+	}
+	return result
+}
+
+func (p polynomial) scale(c *big.Rat) polynomial {
+	result := newPolynomial()
+	for _, t := range p.terms {
+		result.addTerm(new(big.Rat).Mul(t.coefficient, c), t.monomial)
+	}
+	return result
+}
+
+func (p polynomial) sub(q polynomial) polynomial {
+	return p.add(q.scale(big.NewRat(-1, 1)))
+}
+
+func (p polynomial) mul(q polynomial) polynomial {
+	result := newPolynomial()
+	for _, t1 := range p.terms {
+		for _, t2 := range q.terms {
+			result.addTerm(new(big.Rat).Mul(t1.coefficient, t2.coefficient), t1.monomial.times(t2.monomial))
+		}
+	}
+	return result
+}
+
+func (p polynomial) pow(n int) polynomial {
+	result := constantPolynomial(big.NewRat(1, 1))
+	for i := 0; i < n; i++ {
+		result = result.mul(p)
+	}
+	return result
+}
+
+func (p polynomial) variables() []string {
+	seen := make(map[string]bool)
+	names := make([]string, 0)
+	for _, t := range p.terms {
+		for name := range t.monomial {
+			if !seen[name] {
+				seen[name] = true
+				names = append(names, name)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (p polynomial) degree(name string) int {
+	degree := 0
+	for _, t := range p.terms {
+		if t.monomial[name] > degree {
+			degree = t.monomial[name]
+		}
+	}
+	return degree
+}
+
+func (p polynomial) sortedTerms() []polyTerm {
+	terms := make([]polyTerm, 0, len(p.terms))
+	for _, t := range p.terms {
+		terms = append(terms, t)
+	}
+	sort.Slice(terms, func(i, j int) bool {
+		di, dj := terms[i].monomial.degree(), terms[j].monomial.degree()
+		if di != dj {
+			return di > dj
+		}
+		return terms[i].monomial.before(terms[j].monomial)
+	})
+	return terms
+}
+
+func (m monomial) before(other monomial) bool {
+	names := make([]string, 0, len(m)+len(other))
+	for name := range m {
+		names = append(names, name)
+	}
+	for name := range other {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if m[name] != other[name] {
+			return m[name] > other[name]
+		}
+	}
+	return false
+}
+
+func toPolynomial(val value) (polynomial, error) {
+	switch val.op {
+	default:
+		return polynomial{}, errors.New(val.String() + " is not a polynomial")
+	case "num":
+		if !isFinite(val.number) || val.imaginary != 0 {
+			return polynomial{}, errors.New(val.String() + " is not a polynomial")
+		}
+		return constantPolynomial(ratFromFloat(val.number)), nil
+	case "var":
+		if val.exponent < 0 || val.exponent != math.Trunc(val.exponent) {
+			return polynomial{}, errors.New(val.String() + " is not a polynomial")
+		}
+		return monomialPolynomial(ratFromFloat(val.number), val.name, int(val.exponent)), nil
+	case "+", "-", "*":
+		l, err := toPolynomial(*val.left)
+		if err != nil {
+			return polynomial{}, err
+		}
+		r, err := toPolynomial(*val.right)
+		if err != nil {
+			return polynomial{}, err
+		}
+		switch val.op {
+		case "+":
+			return l.add(r), nil
+		case "-":
+			return l.sub(r), nil
+		default:
+			return l.mul(r), nil
+		}
+	case "/":
+		l, err := toPolynomial(*val.left)
+		if err != nil {
+			return polynomial{}, err
+		}
+		r, err := toPolynomial(*val.right)
+		if err != nil {
+			return polynomial{}, err
+		}
+		c, constant := r.constant()
+		if !constant || c.Sign() == 0 {
+			return polynomial{}, errors.New(val.String() + " is not a polynomial")
+		}
+		return l.scale(new(big.Rat).Inv(c)), nil
+	case "^":
+		exponent := val.right.execute()
+		if exponent.op != "num" || exponent.number < 0 || exponent.number != math.Trunc(exponent.number) {
+			return polynomial{}, errors.New(val.String() + " is not a polynomial")
+		}
+		base, err := toPolynomial(*val.left)
+		if err != nil {
+			return polynomial{}, err
+		}
+		return base.pow(int(exponent.number)), nil
+	}
+}
+
+func fromPolynomial(p polynomial) value {
+	if p.isZero() {
+		return Num(0)
+	}
+
+	var result *value
+	for _, t := range p.sortedTerms() {
+		current := fromTerm(t)
+		if result == nil {
+			result = &current
+		} else {
+			sum := Add(*result, current)
+			result = &sum
+		}
+	}
+	return *result
+}
+
+func fromTerm(t polyTerm) value {
+	c, _ := t.coefficient.Float64()
+	names := make([]string, 0, len(t.monomial))
+	for name := range t.monomial {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	if len(names) == 0 {
+		return Num(c)
+	}
+	result := Var(c, names[0], float64(t.monomial[names[0]]))
+	for _, name := range names[1:] {
+		result = Mul(result, Var(1, name, float64(t.monomial[name])))
+	}
+	return result
+}
+
+func ratFromFloat(f float64) *big.Rat {
+	if f == math.Trunc(f) {
+		return new(big.Rat).SetFloat64(f)
+	}
+
+	h0, h1 := big.NewInt(0), big.NewInt(1)
+	k0, k1 := big.NewInt(1), big.NewInt(0)
+	x := math.Abs(f)
+	for i := 0; i < 40; i++ {
+		a := math.Floor(x)
+		ai := big.NewInt(int64(a))
+		h0, h1 = h1, new(big.Int).Add(new(big.Int).Mul(ai, h1), h0)
+		k0, k1 = k1, new(big.Int).Add(new(big.Int).Mul(ai, k1), k0)
+		candidate := new(big.Rat).SetFrac(h1, k1)
+		if approx, _ := candidate.Float64(); approx == math.Abs(f) {
+			if f < 0 {
+				candidate.Neg(candidate)
+			}
+			return candidate
+		}
+		if x-a == 0 || k1.BitLen() > 52 {
+			break
+		}
+		x = 1 / (x - a)
+	}
+	return new(big.Rat).SetFloat64(f)
+}
@@ -0,0 +1,34 @@
+package equations
+
+import (
+	"math/big"
+	"testing"
+)
+
+func TestToPolynomial(t *testing.T) {
+	expression := Mul(Add(Var(1, "x", 1), Num(1)), Sub(Var(1, "x", 1), Var(1, "y", 1)))
+
+	p, err := toPolynomial(expression)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fromPolynomial(p).String() != "(((1.000000x^2 + (-1.000000x * 1.000000y)) + 1.000000x) + -1.000000y)" {
+		t.Fatalf("expected %v to be (((1.000000x^2 + (-1.000000x * 1.000000y)) + 1.000000x) + -1.000000y)", fromPolynomial(p))
+	}
+}
+
+func TestToPolynomial_notAPolynomial(t *testing.T) {
+	_, err := toPolynomial(Div(Num(1), Var(1, "x", 1)))
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestRatFromFloat(t *testing.T) {
+	if r := ratFromFloat(0.1); r.Cmp(big.NewRat(1, 10)) != 0 {
+		t.Fatalf("expected %v to be 1/10", r)
+	}
+	if r := ratFromFloat(-2.0 / 3.0); r.Cmp(big.NewRat(-2, 3)) != 0 {
+		t.Fatalf("expected %v to be -2/3", r)
+	}
+}
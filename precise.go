@@ -0,0 +1,445 @@
+package equations
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+)
+
+type Simplifier struct {
+	precision uint
+	rounding  big.RoundingMode
+	matchers  []PatternMatcher
+}
+
+var defaultSimplifier = &Simplifier{}
+
+func NewSimplifier() *Simplifier {
+	return &Simplifier{}
+}
+
+func NewBigSimplifier(precision uint, rounding big.RoundingMode) *Simplifier {
+	s := &Simplifier{precision: precision, rounding: rounding}
+	s.matchers = newMatchers(s)
+	return s
+}
+
+func (s *Simplifier) precise() bool {
+	return s != nil && s.precision > 0
+}
+
+func (s *Simplifier) patternMatchers() []PatternMatcher {
+	if s.matchers == nil {
+		return Matchers
+	}
+	return s.matchers
+}
+
+func (s *Simplifier) Num(number string) (value, error) {
+	if !s.precise() {
+		var f float64
+		if _, err := fmt.Sscan(number, &f); err != nil {
+			return value{}, err
+		}
+		return Num(f), nil
+	}
+
+	f, _, err := big.ParseFloat(number, 10, s.precision, s.rounding)
+	if err != nil {
+		return value{}, err
+	}
+	return s.number(f), nil
+}
+
+func (s *Simplifier) Simplify(v value) value {
+	if v.left != nil && v.right != nil {
+		l := s.Simplify(*v.left)
+		r := s.Simplify(*v.right)
+		v.left = &l
+		v.right = &r
+	} else if v.left != nil {
+		l := s.Simplify(*v.left)
+		v.left = &l
+	}
+
+	for _, pm := range s.patternMatchers() {
+		if v.held && expands(pm) {
+			continue
+		}
+		if pm.Match(&v) {
+			return s.Simplify(pm.Execute())
+		}
+	}
+	return v
+}
+
+func (s *Simplifier) newFloat() *big.Float {
+	return new(big.Float).SetPrec(s.precision).SetMode(s.rounding)
+}
+
+func (s *Simplifier) float(v value) *big.Float {
+	if v.precise != nil {
+		return s.newFloat().Set(v.precise)
+	}
+	return s.newFloat().SetFloat64(v.number)
+}
+
+func (s *Simplifier) number(f *big.Float) value {
+	approximation, _ := f.Float64()
+	return value{op: "num", number: approximation, precise: f}
+}
+
+func coefficient(v value) value {
+	return value{op: "num", number: v.number, precise: v.precise}
+}
+
+func term(factor value, name string, exponent float64) value {
+	return value{op: "var", number: factor.number, precise: factor.precise, name: name, exponent: exponent}
+}
+
+func isZeroNumber(v value) bool {
+	if v.precise != nil {
+		return v.precise.Sign() == 0 && v.imaginary == 0
+	}
+	return v.number == 0 && v.imaginary == 0
+}
+
+func (s *Simplifier) foldable(a, b value) bool {
+	if isComplex(a) || isComplex(b) {
+		return true
+	}
+	return !s.precise() || !s.float(a).IsInf() && !s.float(b).IsInf()
+}
+
+func (s *Simplifier) invertible(v value) bool {
+	if isComplex(v) {
+		return true
+	}
+	return !s.precise() || s.float(v).Sign() != 0 && !s.float(v).IsInf()
+}
+
+func (s *Simplifier) powable(base, exponent value) bool {
+	if !s.precise() || isComplex(base) || isComplex(exponent) {
+		return true
+	}
+	if !s.foldable(base, exponent) {
+		return false
+	}
+	b, e := s.float(base), s.float(exponent)
+	if e.IsInt() {
+		return b.Sign() != 0 || e.Sign() >= 0
+	}
+	return b.Sign() > 0
+}
+
+func (s *Simplifier) applicable(name string, v value) bool {
+	if !s.precise() || isComplex(v) {
+		return true
+	}
+	return !s.float(v).IsInf() && (name != "ln" || s.float(v).Sign() > 0)
+}
+
+func (s *Simplifier) neg(v value) value {
+	if isComplex(v) {
+		return fromComplex(-complexOf(v))
+	}
+	if !s.precise() {
+		return Num(-v.number)
+	}
+	return s.number(s.newFloat().Neg(s.float(v)))
+}
+
+func (s *Simplifier) inverse(v value) value {
+	if isComplex(v) {
+		return fromComplex(1 / complexOf(v))
+	}
+	if !s.precise() {
+		return Num(1 / v.number)
+	}
+	return s.number(s.newFloat().Quo(s.newFloat().SetInt64(1), s.float(v)))
+}
+
+func (s *Simplifier) add(a, b value) value {
+	if isComplex(a) || isComplex(b) {
+		return fromComplex(complexOf(a) + complexOf(b))
+	}
+	if !s.precise() {
+		return Num(a.number + b.number)
+	}
+	return s.number(s.newFloat().Add(s.float(a), s.float(b)))
+}
+
+func (s *Simplifier) mul(a, b value) value {
+	if isComplex(a) || isComplex(b) {
+		return fromComplex(complexOf(a) * complexOf(b))
+	}
+	if !s.precise() {
+		return Num(a.number * b.number)
+	}
+	return s.number(s.newFloat().Mul(s.float(a), s.float(b)))
+}
+
+func (s *Simplifier) pow(base, exponent value) value {
+	if isComplex(base) || isComplex(exponent) {
+		return fromComplex(complexPow(complexOf(base), complexOf(exponent)))
+	}
+	if !s.precise() {
+		return Num(math.Pow(base.number, exponent.number))
+	}
+	return s.number(s.bigPow(s.float(base), s.float(exponent)))
+}
+
+func (s *Simplifier) apply(name string, v value) value {
+	if isComplex(v) {
+		return fromComplex(complexFunctions[name](complexOf(v)))
+	}
+	if !s.precise() {
+		return Num(functions[name](v.number))
+	}
+	return s.number(s.bigFunction(name, s.float(v)))
+}
+
+func (s *Simplifier) Evaluate(v value, vars map[string]*big.Float) (*big.Float, error) {
+	if !s.precise() {
+		return nil, errors.New("simplifier does not use arbitrary precision")
+	}
+
+	if _, unary := functions[v.op]; unary {
+		arg, err := s.Evaluate(*v.left, vars)
+		if err != nil {
+			return nil, err
+		}
+		if !s.applicable(v.op, s.number(arg)) {
+			return nil, fmt.Errorf("%v is undefined for %v", v.op, arg)
+		}
+		return s.bigFunction(v.op, arg), nil
+	}
+
+	switch v.op {
+	default:
+		return nil, errors.New("cannot evaluate operator " + v.op)
+	case "num":
+		if v.imaginary != 0 {
+			return nil, errors.New("cannot evaluate complex number " + v.String())
+		}
+		return s.float(v), nil
+	case "const":
+		return s.bigConstant(v), nil
+	case "var":
+		x, present := vars[v.name]
+		if !present {
+			return nil, errors.New("no value for variable " + v.name)
+		}
+		base, exponent := s.number(x), Num(v.exponent)
+		if !s.powable(base, exponent) {
+			return nil, fmt.Errorf("%v^%v is undefined", x, v.exponent)
+		}
+		return s.newFloat().Mul(s.float(coefficient(v)), s.bigPow(s.float(base), s.float(exponent))), nil
+	case "+", "-", "*", "/", "^":
+		l, err := s.Evaluate(*v.left, vars)
+		if err != nil {
+			return nil, err
+		}
+		r, err := s.Evaluate(*v.right, vars)
+		if err != nil {
+			return nil, err
+		}
+		return s.evaluateBinary(v.op, l, r)
+	}
+}
+
+func (s *Simplifier) evaluateBinary(op string, l, r *big.Float) (*big.Float, error) {
+	if (l.IsInf() || r.IsInf()) && op != "+" && op != "-" || l.IsInf() && r.IsInf() {
+		return nil, fmt.Errorf("%v %v %v is undefined", l, op, r)
+	}
+
+	switch op {
+	default:
+		panic("unknown operator " + op)
+	case "+":
+		return s.newFloat().Add(l, r), nil
+	case "-":
+		return s.newFloat().Sub(l, r), nil
+	case "*":
+		return s.newFloat().Mul(l, r), nil
+	case "/":
+		if r.Sign() == 0 {
+			return nil, errors.New("division by zero")
+		}
+		return s.newFloat().Quo(l, r), nil
+	case "^":
+		if !s.powable(s.number(l), s.number(r)) {
+			return nil, fmt.Errorf("%v ^ %v is undefined", l, r)
+		}
+		return s.bigPow(l, r), nil
+	}
+}
+
+func (s *Simplifier) working() *Simplifier {
+	return &Simplifier{precision: s.precision + 64, rounding: big.ToNearestEven}
+}
+
+func (s *Simplifier) bigPow(base, exponent *big.Float) *big.Float {
+	w := s.working()
+	if exponent.IsInt() {
+		n, _ := exponent.Int(nil)
+		result := w.newFloat().SetInt64(1)
+		abs := new(big.Int).Abs(n)
+		for i := abs.BitLen() - 1; i >= 0; i-- {
+			result.Mul(result, result)
+			if abs.Bit(i) == 1 {
+				result.Mul(result, base)
+			}
+		}
+		if n.Sign() < 0 {
+			result.Quo(w.newFloat().SetInt64(1), result)
+		}
+		return s.newFloat().Set(result)
+	}
+
+	exp := w.bigExp(w.newFloat().Mul(exponent, w.bigLn(base)))
+	return s.newFloat().Set(exp)
+}
+
+func (s *Simplifier) bigFunction(name string, x *big.Float) *big.Float {
+	w := s.working()
+	var result *big.Float
+	switch name {
+	default:
+		panic("unknown function " + name)
+	case "exp":
+		result = w.bigExp(x)
+	case "ln":
+		result = w.bigLn(x)
+	case "sin":
+		result = w.bigSin(x)
+	case "cos":
+		result = w.bigCos(x)
+	case "abs":
+		result = new(big.Float).Abs(x)
+	}
+	return s.newFloat().Set(result)
+}
+
+func (s *Simplifier) bigExp(x *big.Float) *big.Float {
+	halvings := 0
+	y := s.newFloat().Set(x)
+	half := s.newFloat().SetFloat64(0.5)
+	for y.MantExp(nil) > -8 && y.Sign() != 0 {
+		y.Mul(y, half)
+		halvings++
+	}
+
+	result := s.newFloat().SetInt64(1)
+	term := s.newFloat().SetInt64(1)
+	epsilon := s.newFloat().SetMantExp(s.newFloat().SetInt64(1), -int(s.precision))
+	for k := int64(1); ; k++ {
+		term.Mul(term, y)
+		term.Quo(term, s.newFloat().SetInt64(k))
+		result.Add(result, term)
+		if s.newFloat().Abs(term).Cmp(epsilon) < 0 {
+			break
+		}
+	}
+
+	for i := 0; i < halvings; i++ {
+		result.Mul(result, result)
+	}
+	return result
+}
+
+func (s *Simplifier) bigLn(x *big.Float) *big.Float {
+	mantissa := s.newFloat()
+	exponent := x.MantExp(mantissa)
+
+	y := s.lnNewton(mantissa)
+	if exponent != 0 {
+		ln2 := s.lnNewton(s.newFloat().SetFloat64(0.5))
+		y.Sub(y, s.newFloat().Mul(ln2, s.newFloat().SetInt64(int64(exponent))))
+	}
+	return y
+}
+
+func (s *Simplifier) lnNewton(x *big.Float) *big.Float {
+	approximation, _ := x.Float64()
+	y := s.newFloat().SetFloat64(math.Log(approximation))
+	two := s.newFloat().SetInt64(2)
+	epsilon := s.newFloat().SetMantExp(s.newFloat().SetInt64(1), -int(s.precision))
+	for i := 0; i < 100; i++ {
+		e := s.bigExp(y)
+		correction := s.newFloat().Quo(s.newFloat().Mul(two, s.newFloat().Sub(x, e)), s.newFloat().Add(x, e))
+		y.Add(y, correction)
+		if s.newFloat().Abs(correction).Cmp(epsilon) < 0 {
+			break
+		}
+	}
+	return y
+}
+
+func (s *Simplifier) bigPi() *big.Float {
+	atan := func(n int64) *big.Float {
+		x := s.newFloat().Quo(s.newFloat().SetInt64(1), s.newFloat().SetInt64(n))
+		x2 := s.newFloat().Mul(x, x)
+		result := s.newFloat().Set(x)
+		power := s.newFloat().Set(x)
+		epsilon := s.newFloat().SetMantExp(s.newFloat().SetInt64(1), -int(s.precision))
+		for k := int64(1); ; k++ {
+			power.Mul(power, x2)
+			term := s.newFloat().Quo(power, s.newFloat().SetInt64(2*k+1))
+			if k%2 == 1 {
+				result.Sub(result, term)
+			} else {
+				result.Add(result, term)
+			}
+			if term.Cmp(epsilon) < 0 {
+				return result
+			}
+		}
+	}
+	pi := s.newFloat().Mul(s.newFloat().SetInt64(16), atan(5))
+	return pi.Sub(pi, s.newFloat().Mul(s.newFloat().SetInt64(4), atan(239)))
+}
+
+func (s *Simplifier) reduceAngle(x *big.Float) *big.Float {
+	w := &Simplifier{precision: s.precision + uint(maxInt(x.MantExp(nil), 0)), rounding: big.ToNearestEven}
+	twoPi := w.newFloat().Mul(w.newFloat().SetInt64(2), w.bigPi())
+	k := w.newFloat().Quo(x, twoPi)
+	turns, _ := k.Int(nil)
+	reduced := w.newFloat().Sub(x, w.newFloat().Mul(twoPi, w.newFloat().SetInt(turns)))
+	return s.newFloat().Set(reduced)
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+func (s *Simplifier) bigSin(x *big.Float) *big.Float {
+	return s.taylor(s.reduceAngle(x), 1)
+}
+
+func (s *Simplifier) bigCos(x *big.Float) *big.Float {
+	return s.taylor(s.reduceAngle(x), 0)
+}
+
+func (s *Simplifier) taylor(x *big.Float, start int64) *big.Float {
+	term := s.newFloat().SetInt64(1)
+	if start == 1 {
+		term.Set(x)
+	}
+	result := s.newFloat().Set(term)
+	x2 := s.newFloat().Mul(x, x)
+	epsilon := s.newFloat().SetMantExp(s.newFloat().SetInt64(1), -int(s.precision))
+	for k := start + 1; ; k += 2 {
+		term.Mul(term, x2)
+		term.Quo(term, s.newFloat().SetInt64(k*(k+1)))
+		term.Neg(term)
+		result.Add(result, term)
+		if s.newFloat().Abs(term).Cmp(epsilon) < 0 && k > 2 {
+			return result
+		}
+	}
+}
@@ -0,0 +1,98 @@
+package equations_test
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"github.com/gossie/equations"
+)
+
+func TestBigSimplifier_division(t *testing.T) {
+	s := equations.NewBigSimplifier(200, big.ToNearestEven)
+	one, _ := s.Num("1")
+	three, _ := s.Num("3")
+
+	result := s.Simplify(equations.Div(one, three)).String()
+	if !strings.HasPrefix(result, "0.33333333333333333333333333333333333333333333333333333333") {
+		t.Fatalf("expected %v to have 56 correct digits", result)
+	}
+}
+
+func TestBigSimplifier_keepsDigitsThroughFolding(t *testing.T) {
+	s := equations.NewBigSimplifier(256, big.ToNearestEven)
+	a, _ := s.Num("1.0000000000000000000000000000000000000000000000001")
+	b, _ := s.Num("2")
+
+	result := s.Simplify(equations.Sub(equations.Mul(a, b), equations.Num(2))).String()
+	f, _, err := big.ParseFloat(result, 10, 256, big.ToNearestEven)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if f.Text('e', 20) != "2.00000000000000000000e-49" {
+		t.Fatalf("expected %v to be 2e-49", result)
+	}
+}
+
+func TestSimplifier_float64(t *testing.T) {
+	s := equations.NewSimplifier()
+
+	result := s.Simplify(equations.Add(equations.Num(1), equations.Num(2)))
+	if result.String() != "3.000000" {
+		t.Fatalf("expected %v to be 3.000000", result)
+	}
+}
+
+func TestBigSimplifier_evaluate(t *testing.T) {
+	s := equations.NewBigSimplifier(200, big.ToNearestEven)
+	vars := map[string]*big.Float{"x": big.NewFloat(1)}
+	x := equations.Var(1, "x", 1)
+
+	result, err := s.Evaluate(equations.Exp(x), vars)
+	assertDigits(t, result, err, "2.71828182845904523536028747135266249775724709369995957496696")
+
+	result, err = s.Evaluate(equations.Ln(equations.Mul(equations.Num(10), x)), vars)
+	assertDigits(t, result, err, "2.30258509299404568401799145468436420760110148862877297603332")
+
+	result, err = s.Evaluate(equations.Sin(x), vars)
+	assertDigits(t, result, err, "0.84147098480789650665250232163029899962256306079837106567275")
+
+	result, err = s.Evaluate(equations.Cos(x), vars)
+	assertDigits(t, result, err, "0.54030230586813971740093660744297660373231042061792222767009")
+
+	result, err = s.Evaluate(equations.Pow(equations.Num(2), equations.Div(x, equations.Num(2))), vars)
+	assertDigits(t, result, err, "1.41421356237309504880168872420969807856967187537694807317667")
+}
+
+func TestBigSimplifier_keepsDigitsInVariableCoefficients(t *testing.T) {
+	s := equations.NewBigSimplifier(200, big.ToNearestEven)
+	one, _ := s.Num("1")
+	three, _ := s.Num("3")
+	third := equations.Div(one, three)
+	x := equations.Var(1, "x", 1)
+
+	simplified := s.Simplify(equations.Add(equations.Mul(third, x), equations.Mul(x, third)))
+	if !strings.HasPrefix(simplified.String(), "0.666666666666666666666666666666666666666666666666666666") {
+		t.Fatalf("expected %v to keep 54 digits of its coefficient", simplified)
+	}
+
+	result, err := s.Evaluate(s.Simplify(equations.Mul(third, equations.Var(3, "x", 1))), map[string]*big.Float{"x": big.NewFloat(1)})
+	assertDigits(t, result, err, "1.00000000000000000000000000000000000000000000000000000000000")
+}
+
+func TestSimplifier_evaluateNeedsPrecision(t *testing.T) {
+	_, err := equations.NewSimplifier().Evaluate(equations.Num(1), nil)
+	if err == nil {
+		t.Fatal("expected an error for a float64 simplifier")
+	}
+}
+
+func assertDigits(t *testing.T, result *big.Float, err error, expected string) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(result.Text('f', 70), expected) {
+		t.Fatalf("expected %v to start with %v", result.Text('f', 70), expected)
+	}
+}
@@ -0,0 +1,133 @@
+package equations
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+func unknown(name string) bool {
+	return !isUnit(name)
+}
+
+func Variables(expr value) []string {
+	seen := make(map[string]bool)
+	var walk func(val value)
+	walk = func(val value) {
+		if val.op == "var" && unknown(val.name) {
+			seen[val.name] = true
+		}
+		if val.left != nil {
+			walk(*val.left)
+		}
+		if val.right != nil {
+			walk(*val.right)
+		}
+	}
+	walk(expr)
+
+	names := make([]string, 0, len(seen))
+	for name := range seen {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func polynomialPowers(expr value, varName string) (map[float64]value, bool) {
+	powers, polynomial := collectPowers(expr, varName)
+	if !polynomial {
+		return nil, false
+	}
+	for exponent, coefficient := range powers {
+		if exponent < 0 || exponent != math.Trunc(exponent) || containsVariable(coefficient, varName) {
+			return nil, false
+		}
+	}
+	return powers, true
+}
+
+func IsPolynomial(expr value, varName string) bool {
+	_, polynomial := polynomialPowers(expr, varName)
+	return polynomial
+}
+
+func IsLinear(expr value, varName string) bool {
+	degree, err := Degree(expr, varName)
+	return err == nil && degree <= 1
+}
+
+func IsRational(expr value, varName string) bool {
+	frozen := freeze(expr, varName, make(map[uint64]string))
+	_, err := toRational(frozen)
+	return err == nil
+}
+
+func freeze(expr value, varName string, placeholders map[uint64]string) value {
+	if expr.op == "num" || expr.op == "var" {
+		return expr
+	}
+	if !containsVariable(expr, varName) {
+		h := Hash(expr)
+		if _, present := placeholders[h]; !present {
+			placeholders[h] = fmt.Sprintf("#%d", len(placeholders)+1)
+		}
+		return Var(1, placeholders[h], 1)
+	}
+
+	if expr.left != nil {
+		l := freeze(*expr.left, varName, placeholders)
+		expr.left = &l
+	}
+	if expr.right != nil {
+		r := freeze(*expr.right, varName, placeholders)
+		expr.right = &r
+	}
+	return expr
+}
+
+func Degree(expr value, varName string) (int, error) {
+	powers, polynomial := polynomialPowers(expr, varName)
+	if !polynomial {
+		return 0, errors.New(expr.String() + " is not a polynomial in " + varName)
+	}
+	degree := 0
+	for exponent := range powers {
+		if int(exponent) > degree {
+			degree = int(exponent)
+		}
+	}
+	return degree, nil
+}
+
+func TotalDegree(expr value) (int, error) {
+	p, err := toPolynomial(SubstituteConstants(expr))
+	if err != nil {
+		return 0, err
+	}
+	degree := 0
+	for _, t := range p.terms {
+		d := 0
+		for name, e := range t.monomial {
+			if unknown(name) {
+				d += e
+			}
+		}
+		if d > degree {
+			degree = d
+		}
+	}
+	return degree, nil
+}
+
+func Coefficient(expr value, varName string, k int) (value, error) {
+	powers, polynomial := polynomialPowers(expr, varName)
+	if !polynomial {
+		return value{}, errors.New(expr.String() + " is not a polynomial in " + varName)
+	}
+	if coefficient, present := powers[float64(k)]; present {
+		return coefficient, nil
+	}
+	return Num(0), nil
+}
@@ -0,0 +1,123 @@
+package equations_test
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/gossie/equations"
+)
+
+func TestVariables(t *testing.T) {
+	metres, _ := equations.Quantity(2, "m")
+	expr := equations.Add(equations.Mul(equations.Var(2, "y", 1), equations.Sin(equations.Var(1, "x", 2))), equations.Mul(equations.Pi(), metres))
+
+	result := equations.Variables(expr)
+	if !reflect.DeepEqual(result, []string{"x", "y"}) {
+		t.Fatalf("expected %v to be [x y]", result)
+	}
+}
+
+func TestDegree(t *testing.T) {
+	expr := equations.Add(equations.Mul(equations.Var(3, "x", 2), equations.Var(1, "y", 3)), equations.Var(1, "x", 1))
+
+	tests := []struct {
+		varName  string
+		expected int
+	}{
+		{"x", 2},
+		{"y", 3},
+		{"z", 0},
+	}
+	for _, test := range tests {
+		degree, err := equations.Degree(expr, test.varName)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if degree != test.expected {
+			t.Fatalf("expected degree of %v in %v to be %v, got %v", expr, test.varName, test.expected, degree)
+		}
+	}
+
+	total, _ := equations.TotalDegree(expr)
+	if total != 5 {
+		t.Fatalf("expected total degree of %v to be 5, got %v", expr, total)
+	}
+
+	scaled := equations.Mul(equations.Pi(), equations.Var(1, "x", 1))
+	if total, _ := equations.TotalDegree(scaled); total != 1 {
+		t.Fatalf("expected total degree of %v to be 1, got %v", scaled, total)
+	}
+
+	if _, err := equations.Degree(equations.Div(equations.Num(1), equations.Var(1, "x", 1)), "x"); err == nil {
+		t.Fatal("expected 1/x not to have a degree")
+	}
+}
+
+func TestIsPolynomial(t *testing.T) {
+	tests := []struct {
+		name                         string
+		linear, polynomial, rational bool
+	}{
+		{"3x + y", true, true, true},
+		{"x^2 sin(y)", false, true, true},
+		{"(x + 1) / y", true, true, true},
+		{"1 / (x + 1)", false, false, true},
+		{"sin(x)", false, false, false},
+		{"x^0.5", false, false, false},
+	}
+	exprs := map[string]func() (bool, bool, bool){
+		"3x + y": func() (bool, bool, bool) {
+			e := equations.Add(equations.Var(3, "x", 1), equations.Var(1, "y", 1))
+			return equations.IsLinear(e, "x"), equations.IsPolynomial(e, "x"), equations.IsRational(e, "x")
+		},
+		"x^2 sin(y)": func() (bool, bool, bool) {
+			e := equations.Mul(equations.Var(1, "x", 2), equations.Sin(equations.Var(1, "y", 1)))
+			return equations.IsLinear(e, "x"), equations.IsPolynomial(e, "x"), equations.IsRational(e, "x")
+		},
+		"(x + 1) / y": func() (bool, bool, bool) {
+			e := equations.Div(equations.Add(equations.Var(1, "x", 1), equations.Num(1)), equations.Var(1, "y", 1))
+			return equations.IsLinear(e, "x"), equations.IsPolynomial(e, "x"), equations.IsRational(e, "x")
+		},
+		"1 / (x + 1)": func() (bool, bool, bool) {
+			e := equations.Div(equations.Num(1), equations.Add(equations.Var(1, "x", 1), equations.Num(1)))
+			return equations.IsLinear(e, "x"), equations.IsPolynomial(e, "x"), equations.IsRational(e, "x")
+		},
+		"sin(x)": func() (bool, bool, bool) {
+			e := equations.Sin(equations.Var(1, "x", 1))
+			return equations.IsLinear(e, "x"), equations.IsPolynomial(e, "x"), equations.IsRational(e, "x")
+		},
+		"x^0.5": func() (bool, bool, bool) {
+			e := equations.Var(1, "x", 0.5)
+			return equations.IsLinear(e, "x"), equations.IsPolynomial(e, "x"), equations.IsRational(e, "x")
+		},
+	}
+
+	for _, test := range tests {
+		linear, polynomial, rational := exprs[test.name]()
+		if linear != test.linear || polynomial != test.polynomial || rational != test.rational {
+			t.Fatalf("expected %v to be linear=%v polynomial=%v rational=%v, got %v %v %v", test.name, test.linear, test.polynomial, test.rational, linear, polynomial, rational)
+		}
+	}
+}
+
+func TestCoefficient(t *testing.T) {
+	expr := equations.Add(equations.Mul(equations.Var(1, "a", 1), equations.Var(1, "x", 2)), equations.Add(equations.Mul(equations.Var(1, "b", 1), equations.Var(1, "x", 1)), equations.Var(5, "x", 2)))
+
+	tests := []struct {
+		k        int
+		expected string
+	}{
+		{2, "(1.000000a + 5.000000)"},
+		{1, "1.000000b"},
+		{0, "0.000000"},
+	}
+	for _, test := range tests {
+		coefficient, err := equations.Coefficient(expr, "x", test.k)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if coefficient.String() != test.expected {
+			t.Fatalf("expected coefficient of x^%v to be %v, got %v", test.k, test.expected, coefficient)
+		}
+	}
+}
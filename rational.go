@@ -0,0 +1,302 @@
+package equations
+
+import (
+	"errors"
+	"math"
+	"math/big"
+)
+
+type rationalFunction struct {
+	numerator, denominator polynomial
+}
+
+func one() polynomial {
+	return constantPolynomial(big.NewRat(1, 1))
+}
+
+func toRational(val value) (rationalFunction, error) {
+	switch val.op {
+	default:
+		return rationalFunction{}, errors.New(val.String() + " is not a rational function")
+	case "num":
+		p, err := toPolynomial(val)
+		return rationalFunction{p, one()}, err
+	case "var":
+		if val.exponent != math.Trunc(val.exponent) {
+			return rationalFunction{}, errors.New(val.String() + " is not a rational function")
+		}
+		if val.exponent < 0 {
+			return rationalFunction{constantPolynomial(ratFromFloat(val.number)), monomialPolynomial(big.NewRat(1, 1), val.name, int(-val.exponent))}, nil
+		}
+		p, err := toPolynomial(val)
+		return rationalFunction{p, one()}, err
+	case "+", "-", "*", "/":
+		l, err := toRational(*val.left)
+		if err != nil {
+			return rationalFunction{}, err
+		}
+		r, err := toRational(*val.right)
+		if err != nil {
+			return rationalFunction{}, err
+		}
+		switch val.op {
+		case "+":
+			return l.add(r), nil
+		case "-":
+			return l.add(rationalFunction{r.numerator.scale(big.NewRat(-1, 1)), r.denominator}), nil
+		case "*":
+			return rationalFunction{l.numerator.mul(r.numerator), l.denominator.mul(r.denominator)}, nil
+		default:
+			if r.numerator.isZero() {
+				return rationalFunction{}, errors.New("division by zero in " + val.String())
+			}
+			return rationalFunction{l.numerator.mul(r.denominator), l.denominator.mul(r.numerator)}, nil
+		}
+	case "^":
+		exponent := val.right.execute()
+		if exponent.op != "num" || exponent.number != math.Trunc(exponent.number) {
+			return rationalFunction{}, errors.New(val.String() + " is not a rational function")
+		}
+		base, err := toRational(*val.left)
+		if err != nil {
+			return rationalFunction{}, err
+		}
+		n := int(exponent.number)
+		if n < 0 {
+			if base.numerator.isZero() {
+				return rationalFunction{}, errors.New("division by zero in " + val.String())
+			}
+			return rationalFunction{base.denominator.pow(-n), base.numerator.pow(-n)}, nil
+		}
+		return rationalFunction{base.numerator.pow(n), base.denominator.pow(n)}, nil
+	}
+}
+
+func (r rationalFunction) add(other rationalFunction) rationalFunction {
+	if r.denominator.sub(other.denominator).isZero() {
+		return rationalFunction{r.numerator.add(other.numerator), r.denominator}
+	}
+	numerator := r.numerator.mul(other.denominator).add(other.numerator.mul(r.denominator))
+	return rationalFunction{numerator, r.denominator.mul(other.denominator)}
+}
+
+func (r rationalFunction) value() value {
+	if c, constant := r.denominator.constant(); constant {
+		return fromPolynomial(r.numerator.scale(new(big.Rat).Inv(c)))
+	}
+	return Div(fromPolynomial(r.numerator), fromPolynomial(r.denominator))
+}
+
+func SimplifyRational(expr value) (value, []Condition, error) {
+	r, err := toRational(expr)
+	if err != nil {
+		return value{}, nil, err
+	}
+
+	conditions := make([]Condition, 0)
+	g := polynomialGCD(r.numerator, r.denominator)
+	if _, constant := g.constant(); !constant {
+		r.numerator, _ = divide(r.numerator, g)
+		r.denominator, _ = divide(r.denominator, g)
+		removed, _ := divide(g, polynomialGCD(g, r.denominator))
+		if _, constant := removed.constant(); !constant {
+			conditions = append(conditions, notZero(fromPolynomial(removed)))
+		}
+	}
+	return r.normalized().value(), conditions, nil
+}
+
+func (r rationalFunction) normalized() rationalFunction {
+	if r.denominator.isZero() || r.numerator.isZero() {
+		return rationalFunction{r.numerator, one()}
+	}
+	lead := r.denominator.sortedTerms()[0].coefficient
+	factor := new(big.Rat).Inv(lead)
+	return rationalFunction{r.numerator.scale(factor), r.denominator.scale(factor)}
+}
+
+func polynomialGCD(a, b polynomial) polynomial {
+	if a.isZero() {
+		return b
+	}
+	if b.isZero() {
+		return a
+	}
+
+	vars := a.add(b).variables()
+	if len(vars) == 0 {
+		return one()
+	}
+	if len(vars) == 1 {
+		return fromUnivariate(ugcd(a.univariate(vars[0]), b.univariate(vars[0])), vars[0])
+	}
+
+	x := vars[0]
+	contentA, p := a.primitive(x)
+	contentB, q := b.primitive(x)
+	if p.degree(x) < q.degree(x) {
+		p, q = q, p
+	}
+	for !q.isZero() {
+		if q.degree(x) == 0 {
+			p = one()
+			break
+		}
+		r := pseudoRemainder(p, q, x)
+		p = q
+		if r.isZero() {
+			q = r
+		} else {
+			_, q = r.primitive(x)
+		}
+	}
+	return polynomialGCD(contentA, contentB).mul(p).monic()
+}
+
+func (p polynomial) coefficient(name string, degree int) polynomial {
+	result := newPolynomial()
+	for _, t := range p.terms {
+		if t.monomial[name] != degree {
+			continue
+		}
+		m := make(monomial)
+		for other, e := range t.monomial {
+			if other != name {
+				m[other] = e
+			}
+		}
+		result.addTerm(t.coefficient, m)
+	}
+	return result
+}
+
+func (p polynomial) primitive(name string) (polynomial, polynomial) {
+	content := newPolynomial()
+	for d := p.degree(name); d >= 0; d-- {
+		content = polynomialGCD(content, p.coefficient(name, d))
+	}
+	content = content.monic()
+	primitive, _ := divide(p, content)
+	return content, primitive
+}
+
+func pseudoRemainder(p, q polynomial, name string) polynomial {
+	degree := q.degree(name)
+	lead := q.coefficient(name, degree)
+	for !p.isZero() && p.degree(name) >= degree {
+		shift := monomialPolynomial(big.NewRat(1, 1), name, p.degree(name)-degree)
+		p = p.mul(lead).sub(p.coefficient(name, p.degree(name)).mul(q).mul(shift))
+	}
+	return p
+}
+
+func (p polynomial) monic() polynomial {
+	if p.isZero() {
+		return p
+	}
+	return p.scale(new(big.Rat).Inv(p.sortedTerms()[0].coefficient))
+}
+
+func divide(p, d polynomial) (polynomial, bool) {
+	quotient := newPolynomial()
+	remainder := p.add(newPolynomial())
+	lead := d.sortedTerms()[0]
+	for !remainder.isZero() {
+		t := remainder.sortedTerms()[0]
+		m := make(monomial)
+		for name, e := range t.monomial {
+			m[name] = e
+		}
+		for name, e := range lead.monomial {
+			if m[name] < e {
+				return quotient, false
+			}
+			m[name] -= e
+			if m[name] == 0 {
+				delete(m, name)
+			}
+		}
+		q := newPolynomial()
+		q.addTerm(new(big.Rat).Quo(t.coefficient, lead.coefficient), m)
+		quotient = quotient.add(q)
+		remainder = remainder.sub(q.mul(d))
+	}
+	return quotient, true
+}
+
+func Apart(expr value, varName string) (value, error) {
+	r, err := toRational(expr)
+	if err != nil {
+		return value{}, err
+	}
+	for _, name := range r.numerator.add(r.denominator).variables() {
+		if name != varName {
+			return value{}, errors.New("partial fractions need a univariate rational function in " + varName)
+		}
+	}
+
+	numerator, denominator := r.numerator.univariate(varName), r.denominator.univariate(varName)
+	g := ugcd(numerator, denominator)
+	numerator, _ = numerator.divmod(g)
+	denominator, _ = denominator.divmod(g)
+	quotient, remainder := numerator.divmod(denominator)
+
+	terms := make([]value, 0)
+	if len(quotient) > 0 {
+		terms = append(terms, fromPolynomial(fromUnivariate(quotient, varName)))
+	}
+	if len(remainder) == 0 {
+		return sum(terms), nil
+	}
+
+	scale := new(big.Rat).Inv(denominator.lead())
+	remainder = remainder.scale(scale)
+	rest := denominator.monic()
+	for _, f := range factorUnivariate(denominator) {
+		power := upoly{big.NewRat(1, 1)}
+		for i := 0; i < f.multiplicity; i++ {
+			power = power.mul(f.base)
+		}
+		power = power.monic()
+		cofactor, _ := rest.divmod(power)
+
+		s, _ := extendedEuclid(cofactor, power)
+		_, a := remainder.mul(s).divmod(power)
+		b, _ := remainder.sub(a.mul(cofactor)).divmod(power)
+
+		base := f.base.monic()
+		fractions := make([]value, 0, f.multiplicity)
+		for k := f.multiplicity; k >= 1 && len(a) > 0; k-- {
+			q, c := a.divmod(base)
+			if len(c) > 0 {
+				fractions = append([]value{partialFraction(c, base, k, varName)}, fractions...)
+			}
+			a = q
+		}
+		terms = append(terms, fractions...)
+		remainder, rest = b, cofactor
+	}
+	return sum(terms), nil
+}
+
+func partialFraction(numerator, base upoly, power int, varName string) value {
+	denominator := fromPolynomial(fromUnivariate(base, varName))
+	if power > 1 {
+		denominator = hold(Pow(denominator, Num(float64(power))))
+	}
+	return Div(fromPolynomial(fromUnivariate(numerator, varName)), denominator)
+}
+
+func extendedEuclid(a, b upoly) (upoly, upoly) {
+	oldR, r := a.trim(), b.trim()
+	oldS, s := upoly{big.NewRat(1, 1)}, upoly{}
+	oldT, t := upoly{}, upoly{big.NewRat(1, 1)}
+	for len(r) > 0 {
+		q, remainder := oldR.divmod(r)
+		oldR, r = r, remainder
+		oldS, s = s, oldS.sub(q.mul(s))
+		oldT, t = t, oldT.sub(q.mul(t))
+	}
+	inverse := new(big.Rat).Inv(oldR.lead())
+	return oldS.scale(inverse), oldT.scale(inverse)
+}
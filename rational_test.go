@@ -0,0 +1,110 @@
+package equations_test
+
+import (
+	"testing"
+
+	"github.com/gossie/equations"
+)
+
+func TestSimplifyRational_cancel(t *testing.T) {
+	quotient := equations.Div(equations.Sub(equations.Var(1, "x", 2), equations.Num(1)), equations.Sub(equations.Var(1, "x", 1), equations.Num(1)))
+
+	simplified, conditions, err := equations.SimplifyRational(quotient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if simplified.String() != "(1.000000x + 1.000000)" {
+		t.Fatalf("expected %v to be (1.000000x + 1.000000)", simplified)
+	}
+	if len(conditions) != 1 || conditions[0].String() != "(1.000000x + -1.000000) != 0.000000" {
+		t.Fatalf("expected %v to be [(1.000000x + -1.000000) != 0.000000]", conditions)
+	}
+}
+
+func TestSimplifyRational_multivariate(t *testing.T) {
+	quotient := equations.Div(equations.Sub(equations.Var(1, "x", 2), equations.Var(1, "y", 2)), equations.Add(equations.Var(2, "x", 1), equations.Var(2, "y", 1)))
+
+	simplified, conditions, err := equations.SimplifyRational(quotient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if simplified.String() != "(0.500000x + -0.500000y)" {
+		t.Fatalf("expected %v to be (0.500000x + -0.500000y)", simplified)
+	}
+	if len(conditions) != 1 || conditions[0].String() != "(1.000000x + 1.000000y) != 0.000000" {
+		t.Fatalf("expected %v to be [(1.000000x + 1.000000y) != 0.000000]", conditions)
+	}
+}
+
+func TestSimplifyRational_multivariateIrreducibleFactor(t *testing.T) {
+	numerator := equations.Add(equations.Add(equations.Var(1, "x", 2), equations.Mul(equations.Var(3, "x", 1), equations.Var(1, "y", 1))), equations.Var(2, "y", 2))
+	quotient := equations.Div(numerator, equations.Sub(equations.Var(1, "x", 2), equations.Var(1, "y", 2)))
+
+	simplified, conditions, err := equations.SimplifyRational(quotient)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if simplified.String() != "((1.000000x + 2.000000y) / (1.000000x + -1.000000y))" {
+		t.Fatalf("expected %v to be ((1.000000x + 2.000000y) / (1.000000x + -1.000000y))", simplified)
+	}
+	if len(conditions) != 1 || conditions[0].String() != "(1.000000x + 1.000000y) != 0.000000" {
+		t.Fatalf("expected %v to be [(1.000000x + 1.000000y) != 0.000000]", conditions)
+	}
+}
+
+func TestSimplifyRational_commonDenominator(t *testing.T) {
+	sum := equations.Add(equations.Div(equations.Num(1), equations.Var(1, "x", 1)), equations.Div(equations.Num(1), equations.Add(equations.Var(1, "x", 1), equations.Num(1))))
+
+	simplified, conditions, err := equations.SimplifyRational(sum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if simplified.String() != "((2.000000x + 1.000000) / (1.000000x^2 + 1.000000x))" {
+		t.Fatalf("expected %v to be ((2.000000x + 1.000000) / (1.000000x^2 + 1.000000x))", simplified)
+	}
+	if len(conditions) != 0 {
+		t.Fatalf("expected %v to be empty", conditions)
+	}
+}
+
+func TestSimplifyRational_keepsRemainingPoles(t *testing.T) {
+	sum := equations.Add(equations.Div(equations.Num(1), equations.Sub(equations.Var(1, "x", 1), equations.Num(1))), equations.Div(equations.Num(1), equations.Sub(equations.Var(1, "x", 1), equations.Num(1))))
+
+	simplified, conditions, err := equations.SimplifyRational(sum)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if simplified.String() != "(2.000000 / (1.000000x + -1.000000))" {
+		t.Fatalf("expected %v to be (2.000000 / (1.000000x + -1.000000))", simplified)
+	}
+	if len(conditions) != 0 {
+		t.Fatalf("expected %v to be empty", conditions)
+	}
+}
+
+func TestApart(t *testing.T) {
+	denominator := equations.Mul(equations.Add(equations.Var(1, "x", 1), equations.Num(1)), equations.Add(equations.Var(1, "x", 1), equations.Num(2)))
+	quotient := equations.Div(equations.Add(equations.Var(3, "x", 1), equations.Num(5)), denominator)
+
+	decomposed, err := equations.Apart(quotient, "x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if decomposed.String() != "((2.000000 / (1.000000x + 1.000000)) + (1.000000 / (1.000000x + 2.000000)))" {
+		t.Fatalf("expected %v to be ((2.000000 / (1.000000x + 1.000000)) + (1.000000 / (1.000000x + 2.000000)))", decomposed)
+	}
+}
+
+func TestApart_repeatedFactorAndPolynomialPart(t *testing.T) {
+	denominator := equations.Pow(equations.Sub(equations.Var(1, "x", 1), equations.Num(1)), equations.Num(2))
+	quotient := equations.Div(equations.Add(equations.Var(1, "x", 3), equations.Num(1)), denominator)
+
+	decomposed, err := equations.Apart(quotient, "x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "(((1.000000x + 2.000000) + (3.000000 / (1.000000x + -1.000000))) + (2.000000 / ((1.000000x + -1.000000) ^ 2.000000)))"
+	if decomposed.String() != expected {
+		t.Fatalf("expected %v to be %v", decomposed, expected)
+	}
+}
@@ -0,0 +1,306 @@
+package equations
+
+import (
+	"strings"
+	"unicode/utf8"
+)
+
+type RenderStyle int
+
+const (
+	ASCII RenderStyle = iota
+	Unicode
+)
+
+var superscripts = map[rune]rune{
+	'0': '⁰', '1': '¹', '2': '²', '3': '³', '4': '⁴',
+	'5': '⁵', '6': '⁶', '7': '⁷', '8': '⁸', '9': '⁹',
+	'-': '⁻', '+': '⁺', 'n': 'ⁿ', 'i': 'ⁱ',
+}
+
+type box struct {
+	lines    []string
+	baseline int
+}
+
+func textBox(s string) box {
+	return box{[]string{s}, 0}
+}
+
+func (b box) width() int {
+	width := 0
+	for _, line := range b.lines {
+		if w := utf8.RuneCountInString(line); w > width {
+			width = w
+		}
+	}
+	return width
+}
+
+func (b box) height() int {
+	return len(b.lines)
+}
+
+func (b box) String() string {
+	lines := make([]string, len(b.lines))
+	for i, line := range b.lines {
+		lines[i] = strings.TrimRight(line, " ")
+	}
+	return strings.Join(lines, "\n")
+}
+
+func padRight(s string, width int) string {
+	return s + strings.Repeat(" ", width-utf8.RuneCountInString(s))
+}
+
+func centered(s string, width int) string {
+	left := (width - utf8.RuneCountInString(s)) / 2
+	return padRight(strings.Repeat(" ", left)+s, width)
+}
+
+func hconcat(boxes ...box) box {
+	above, below := 0, 0
+	for _, b := range boxes {
+		above = maxInt(above, b.baseline)
+		below = maxInt(below, b.height()-b.baseline-1)
+	}
+
+	lines := make([]string, above+below+1)
+	for _, b := range boxes {
+		width := b.width()
+		offset := above - b.baseline
+		for i := range lines {
+			line := ""
+			if i >= offset && i-offset < b.height() {
+				line = b.lines[i-offset]
+			}
+			lines[i] += padRight(line, width)
+		}
+	}
+	return box{lines, above}
+}
+
+func fractionBox(numerator, denominator box, style RenderStyle) box {
+	width := maxInt(numerator.width(), denominator.width()) + 2
+	bar := "-"
+	if style == Unicode {
+		bar = "─"
+	}
+
+	lines := make([]string, 0, numerator.height()+denominator.height()+1)
+	for _, line := range numerator.lines {
+		lines = append(lines, centered(padRight(line, numerator.width()), width))
+	}
+	lines = append(lines, strings.Repeat(bar, width))
+	for _, line := range denominator.lines {
+		lines = append(lines, centered(padRight(line, denominator.width()), width))
+	}
+	return box{lines, numerator.height()}
+}
+
+func superscript(s string) (string, bool) {
+	var b strings.Builder
+	for _, r := range s {
+		sup, present := superscripts[r]
+		if !present {
+			return "", false
+		}
+		b.WriteRune(sup)
+	}
+	return b.String(), true
+}
+
+func powerBox(base, exponent box, style RenderStyle) box {
+	if style == Unicode && exponent.height() == 1 && base.height() == 1 {
+		if sup, ok := superscript(exponent.lines[0]); ok {
+			return textBox(base.lines[0] + sup)
+		}
+	}
+
+	width := base.width()
+	lines := make([]string, 0, exponent.height()+base.height())
+	for _, line := range exponent.lines {
+		lines = append(lines, strings.Repeat(" ", width)+line)
+	}
+	for _, line := range base.lines {
+		lines = append(lines, padRight(line, width))
+	}
+	return box{lines, exponent.height() + base.baseline}
+}
+
+func parenBox(b box, style RenderStyle) box {
+	if b.height() == 1 {
+		return hconcat(textBox("("), b, textBox(")"))
+	}
+
+	top, middle, bottom := [2]string{"/", "\\"}, [2]string{"|", "|"}, [2]string{"\\", "/"}
+	if style == Unicode {
+		top, middle, bottom = [2]string{"⎛", "⎞"}, [2]string{"⎜", "⎟"}, [2]string{"⎝", "⎠"}
+	}
+	left, right := make([]string, b.height()), make([]string, b.height())
+	for i := range left {
+		switch i {
+		case 0:
+			left[i], right[i] = top[0], top[1]
+		case b.height() - 1:
+			left[i], right[i] = bottom[0], bottom[1]
+		default:
+			left[i], right[i] = middle[0], middle[1]
+		}
+	}
+	return hconcat(box{left, b.baseline}, b, box{right, b.baseline})
+}
+
+func render(val value, style RenderStyle) box {
+	if val.op == "abs" {
+		return hconcat(textBox("|"), render(*val.left, style), textBox("|"))
+	}
+	if _, unary := functions[val.op]; unary {
+		return hconcat(textBox(val.op), parenBox(render(*val.left, style), style))
+	}
+
+	switch val.op {
+	default:
+		panic("unknown operator: " + val.op)
+	case "num":
+		switch {
+		case val.imaginary == 0:
+		case val.number == 0:
+			return textBox(formatNumber(val.imaginary) + "i")
+		case val.imaginary < 0:
+			return textBox(formatNumber(val.number) + " - " + formatNumber(-val.imaginary) + "i")
+		default:
+			return textBox(formatNumber(val.number) + " + " + formatNumber(val.imaginary) + "i")
+		}
+		return textBox(formatNumber(val.number))
+	case "const":
+		return textBox(val.name)
+	case "var":
+		term := textBox(val.name)
+		if val.exponent != 1 {
+			term = powerBox(term, textBox(formatNumber(val.exponent)), style)
+		}
+		switch val.number {
+		case 1:
+			return term
+		case -1:
+			return hconcat(textBox("-"), term)
+		}
+		return hconcat(textBox(formatNumber(val.number)), term)
+	case "matrix":
+		return matrixBox(val, style)
+	case "row":
+		return matrixBox(value{op: "matrix", left: &val}, style)
+	case "/":
+		return fractionBox(render(*val.left, style), render(*val.right, style), style)
+	case "^":
+		return powerBox(renderOperand(*val.left, val.op, false, style), render(*val.right, style), style)
+	case "+", "-", "*":
+		operator := " " + val.op + " "
+		right := *val.right
+		positive, negative := negated(right)
+		switch {
+		case val.op == "*" && style == Unicode:
+			operator = " · "
+		case val.op == "+" && negative:
+			operator, right = " - ", positive
+		case val.op == "-" && negative:
+			operator, right = " + ", positive
+		}
+		return hconcat(renderOperand(*val.left, val.op, false, style), textBox(operator), renderOperand(right, val.op, true, style))
+	}
+}
+
+func matrixBox(val value, style RenderStyle) box {
+	m, err := MatrixOf(val)
+	if err != nil {
+		return textBox(val.String())
+	}
+
+	cells := make([]box, len(m.entries))
+	widths := make([]int, m.columns)
+	for i, entry := range m.entries {
+		cells[i] = render(entry, style)
+		widths[i%m.columns] = maxInt(widths[i%m.columns], cells[i].width())
+	}
+
+	lines := make([]string, 0, m.rows)
+	for i := 0; i < m.rows; i++ {
+		row := make([]box, 0, 2*m.columns)
+		for j := 0; j < m.columns; j++ {
+			cell := cells[i*m.columns+j]
+			if j > 0 {
+				row = append(row, textBox("  "))
+			}
+			row = append(row, cell, textBox(strings.Repeat(" ", widths[j]-cell.width())))
+		}
+		lines = append(lines, hconcat(row...).lines...)
+	}
+
+	left, right := make([]string, len(lines)), make([]string, len(lines))
+	for i := range lines {
+		left[i], right[i] = "[", "]"
+		if style == Unicode && len(lines) > 1 {
+			switch i {
+			case 0:
+				left[i], right[i] = "⎡", "⎤"
+			case len(lines) - 1:
+				left[i], right[i] = "⎣", "⎦"
+			default:
+				left[i], right[i] = "⎢", "⎥"
+			}
+		}
+	}
+	baseline := (len(lines) - 1) / 2
+	return hconcat(box{left, baseline}, box{lines, baseline}, box{right, baseline})
+}
+
+func negated(val value) (value, bool) {
+	switch {
+	case val.op == "num" && val.imaginary == 0 && val.number < 0:
+		return Num(-val.number), true
+	case val.op == "var" && val.number < 0:
+		return Var(-val.number, val.name, val.exponent), true
+	case val.op == "*" || val.op == "/":
+		if left, negative := negated(*val.left); negative {
+			val.left = &left
+			return val, true
+		}
+	}
+	return val, false
+}
+
+func renderOperand(val value, parent string, right bool, style RenderStyle) box {
+	precedence, binary := precedences[val.op]
+	if val.op == "var" && (val.number != 1 || val.exponent != 1) && parent == "^" {
+		binary, precedence = true, 0
+	}
+	if binary && (val.op != "/" || parent == "^") && (precedence < precedences[parent] || right && precedence == precedences[parent] && parent != "+" && parent != "*") {
+		return parenBox(render(val, style), style)
+	}
+	return render(val, style)
+}
+
+func Render(val value, style RenderStyle) string {
+	return render(val, style).String()
+}
+
+func RenderEquation(eq equation, style RenderStyle) string {
+	return RenderSystem(style, eq)
+}
+
+func RenderSystem(style RenderStyle, eqs ...equation) string {
+	lefts := make([]box, len(eqs))
+	width := 0
+	for i, eq := range eqs {
+		lefts[i] = render(eq.left, style)
+		width = maxInt(width, lefts[i].width())
+	}
+
+	rendered := make([]string, len(eqs))
+	for i, eq := range eqs {
+		indent := textBox(strings.Repeat(" ", width-lefts[i].width()))
+		rendered[i] = hconcat(indent, lefts[i], textBox(" = "), render(eq.right, style)).String()
+	}
+	return strings.Join(rendered, "\n")
+}
@@ -0,0 +1,72 @@
+package equations_test
+
+import (
+	"testing"
+
+	"github.com/gossie/equations"
+)
+
+func TestRender_fraction(t *testing.T) {
+	result := equations.Render(equations.Div(equations.Add(equations.Var(1, "x", 1), equations.Num(1)), equations.Sub(equations.Var(1, "y", 1), equations.Num(3))), equations.ASCII)
+
+	expected := " x + 1\n-------\n y - 3"
+	if result != expected {
+		t.Fatalf("expected\n%v\nto be\n%v", result, expected)
+	}
+}
+
+func TestRender_exponent(t *testing.T) {
+	ascii := equations.Render(equations.Add(equations.Var(2, "x", 2), equations.Num(-1)), equations.ASCII)
+	if ascii != "  2\n2x  - 1" {
+		t.Fatalf("expected\n%v\nto be\n%v", ascii, "  2\n2x  - 1")
+	}
+
+	unicode := equations.Render(equations.Mul(equations.Var(2, "x", 2), equations.Pow(equations.Var(1, "y", 1), equations.Num(-3))), equations.Unicode)
+	if unicode != "2x² · y⁻³" {
+		t.Fatalf("expected %v to be 2x² · y⁻³", unicode)
+	}
+}
+
+func TestRender_raisedFraction(t *testing.T) {
+	result := equations.Render(equations.Pow(equations.Add(equations.Var(1, "x", 1), equations.Num(1)), equations.Div(equations.Num(1), equations.Num(2))), equations.Unicode)
+
+	expected := "        1\n       ───\n        2\n(x + 1)"
+	if result != expected {
+		t.Fatalf("expected\n%v\nto be\n%v", result, expected)
+	}
+}
+
+func TestRender_tallParentheses(t *testing.T) {
+	result := equations.Render(equations.Mul(equations.Num(3), equations.Add(equations.Div(equations.Var(1, "x", 1), equations.Num(2)), equations.Num(1))), equations.Unicode)
+
+	expected := "    ⎛ x     ⎞\n3 · ⎜─── + 1⎟\n    ⎝ 2     ⎠"
+	if result != expected {
+		t.Fatalf("expected\n%v\nto be\n%v", result, expected)
+	}
+}
+
+func TestRenderSystem(t *testing.T) {
+	result := equations.RenderSystem(equations.Unicode,
+		equations.NewEquation(equations.Var(1, "y", 1), equations.Div(equations.Num(1), equations.Var(1, "x", 1))),
+		equations.NewEquation(equations.Add(equations.Var(3, "z", 1), equations.Num(2)), equations.Sin(equations.Var(1, "x", 2))),
+	)
+
+	expected := "          1\n     y = ───\n          x\n3z + 2 = sin(x²)"
+	if result != expected {
+		t.Fatalf("expected\n%v\nto be\n%v", result, expected)
+	}
+}
+
+func TestRender_negativeTerms(t *testing.T) {
+	x, y := equations.Var(1, "x", 1), equations.Var(-2, "y", 1)
+
+	if result := equations.Render(equations.Add(x, y), equations.ASCII); result != "x - 2y" {
+		t.Fatalf("expected %v to be x - 2y", result)
+	}
+	if result := equations.Render(equations.Sub(x, y), equations.ASCII); result != "x + 2y" {
+		t.Fatalf("expected %v to be x + 2y", result)
+	}
+	if result := equations.Render(equations.Add(x, equations.Mul(equations.Num(-3), equations.Sin(x))), equations.Unicode); result != "x - 3 · sin(x)" {
+		t.Fatalf("expected %v to be x - 3 · sin(x)", result)
+	}
+}
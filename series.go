@@ -0,0 +1,364 @@
+package equations
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+type Expansion struct {
+	Polynomial value
+	Point      map[string]float64
+	Order      int
+}
+
+func (e *Expansion) String() string {
+	names := make([]string, 0, len(e.Point))
+	for name := range e.Point {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	offsets := make([]string, 0, len(names))
+	for _, name := range names {
+		offsets = append(offsets, offset(name, e.Point[name]).String())
+	}
+	remainder := fmt.Sprintf("O(%v^%d)", offsets[0], e.Order+1)
+	if len(offsets) > 1 {
+		remainder = fmt.Sprintf("O(|%v|^%d)", strings.Join(offsets, ", "), e.Order+1)
+	}
+	return fmt.Sprintf("%v + %v", e.Polynomial, remainder)
+}
+
+func offset(varName string, x0 float64) value {
+	if x0 == 0 {
+		return Var(1, varName, 1)
+	}
+	return hold(Sub(Var(1, varName, 1), Num(x0)))
+}
+
+func monomialTerm(coefficient value, offsets []value) value {
+	if len(offsets) == 0 {
+		return coefficient
+	}
+	term := offsets[0]
+	for _, o := range offsets[1:] {
+		if term.op == "var" && o.op == "var" && term.name == o.name {
+			term = Var(1, term.name, term.exponent+o.exponent)
+		} else {
+			term = Mul(term, o)
+		}
+	}
+	if term.op == "var" && coefficient.op == "num" && coefficient.imaginary == 0 {
+		return Var(coefficient.number*term.number, term.name, term.exponent)
+	}
+	if coefficient.op == "num" && coefficient.number == 1 {
+		return term
+	}
+	return Mul(coefficient, term)
+}
+
+func power(o value, k int) value {
+	if k == 1 {
+		return o
+	}
+	if o.op == "var" {
+		return Var(o.number, o.name, o.exponent*float64(k))
+	}
+	return hold(Pow(o, Num(float64(k))))
+}
+
+func isZero(val value) bool {
+	return val.op == "num" && val.number == 0 && val.imaginary == 0
+}
+
+var errSingular = errors.New("singular expansion point")
+
+func Series(expr value, varName string, x0 float64, order int) (*Expansion, error) {
+	if order < 0 {
+		return nil, errors.New("order must not be negative")
+	}
+
+	coefficients, err := taylor(expr, varName, x0, order)
+	if err == errSingular {
+		return nil, fmt.Errorf("%v is not analytic at %v = %v", expr, varName, x0)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	terms := make([]value, 0, order+1)
+	for k, coefficient := range coefficients {
+		if coefficient.op == "num" && !isFinite(coefficient.number) {
+			return nil, fmt.Errorf("%v is not analytic at %v = %v", expr, varName, x0)
+		}
+		if isZero(coefficient) {
+			continue
+		}
+		if k == 0 {
+			terms = append(terms, coefficient)
+		} else {
+			terms = append(terms, monomialTerm(coefficient, []value{power(offset(varName, x0), k)}))
+		}
+	}
+	return &Expansion{sum(terms), map[string]float64{varName: x0}, order}, nil
+}
+
+func taylor(val value, varName string, x0 float64, order int) ([]value, error) {
+	if _, unary := functions[val.op]; unary {
+		arg, err := taylor(*val.left, varName, x0, order)
+		if err != nil {
+			return nil, err
+		}
+		return taylorFunction(val.op, arg)
+	}
+
+	switch val.op {
+	default:
+		return nil, errors.New("cannot expand operator " + val.op)
+	case "num", "const":
+		return constantSeries(val, order), nil
+	case "var":
+		if val.name != varName {
+			return constantSeries(val, order), nil
+		}
+		x := constantSeries(Num(x0), order)
+		if order > 0 {
+			x[1] = Num(1)
+		}
+		p, err := powerSeries(x, Num(val.exponent))
+		if err != nil {
+			return nil, err
+		}
+		return productSeries(constantSeries(coefficient(val), order), p), nil
+	case "+", "-", "*", "/", "^":
+		l, err := taylor(*val.left, varName, x0, order)
+		if err != nil {
+			return nil, err
+		}
+		r, err := taylor(*val.right, varName, x0, order)
+		if err != nil {
+			return nil, err
+		}
+		switch val.op {
+		case "+":
+			return combineSeries(l, r, Add), nil
+		case "-":
+			return combineSeries(l, r, Sub), nil
+		case "*":
+			return productSeries(l, r), nil
+		case "/":
+			return quotientSeries(l, r)
+		}
+		if !containsVariable(*val.right, varName) {
+			return powerSeries(l, r[0])
+		}
+		logarithm, err := taylorFunction("ln", l)
+		if err != nil {
+			return nil, err
+		}
+		return taylorFunction("exp", productSeries(r, logarithm))
+	}
+}
+
+func constantSeries(c value, order int) []value {
+	s := make([]value, order+1)
+	s[0] = c
+	for k := 1; k <= order; k++ {
+		s[k] = Num(0)
+	}
+	return s
+}
+
+func combineSeries(a, b []value, op func(value, value) value) []value {
+	s := make([]value, len(a))
+	for k := range a {
+		s[k] = op(a[k], b[k]).execute()
+	}
+	return s
+}
+
+func productSeries(a, b []value) []value {
+	s := make([]value, len(a))
+	for k := range a {
+		terms := make([]value, 0, k+1)
+		for j := 0; j <= k; j++ {
+			terms = append(terms, Mul(a[j], b[k-j]))
+		}
+		s[k] = sum(terms).execute()
+	}
+	return s
+}
+
+func quotientSeries(a, b []value) ([]value, error) {
+	if isZero(b[0]) {
+		return nil, errSingular
+	}
+	s := make([]value, len(a))
+	for k := range a {
+		terms := []value{a[k]}
+		for j := 1; j <= k; j++ {
+			terms = append(terms, Mul(Num(-1), Mul(b[j], s[k-j])))
+		}
+		s[k] = Div(sum(terms), b[0]).execute()
+	}
+	return s, nil
+}
+
+func powerSeries(a []value, p value) ([]value, error) {
+	if p.op == "num" && p.imaginary == 0 && p.number >= 0 && p.number == math.Trunc(p.number) {
+		result, base := constantSeries(Num(1), len(a)-1), a
+		for n := int(p.number); n > 0; n /= 2 {
+			if n%2 == 1 {
+				result = productSeries(result, base)
+			}
+			if n > 1 {
+				base = productSeries(base, base)
+			}
+		}
+		return result, nil
+	}
+	if isZero(a[0]) {
+		return nil, errSingular
+	}
+
+	s := make([]value, len(a))
+	s[0] = Pow(a[0], p).execute()
+	for k := 1; k < len(a); k++ {
+		terms := make([]value, 0, k)
+		for j := 1; j <= k; j++ {
+			weight := Sub(Mul(Add(p, Num(1)), Num(float64(j))), Num(float64(k)))
+			terms = append(terms, Mul(weight, Mul(a[j], s[k-j])))
+		}
+		s[k] = Div(sum(terms), Mul(Num(float64(k)), a[0])).execute()
+	}
+	return s, nil
+}
+
+func taylorFunction(name string, a []value) ([]value, error) {
+	s := make([]value, len(a))
+	switch name {
+	default:
+		return nil, errors.New("cannot expand function " + name)
+	case "exp":
+		s[0] = Exp(a[0]).execute()
+		for k := 1; k < len(a); k++ {
+			s[k] = Div(weightedSum(a, s, k), Num(float64(k))).execute()
+		}
+	case "sin", "cos":
+		c := make([]value, len(a))
+		s[0], c[0] = Sin(a[0]).execute(), Cos(a[0]).execute()
+		for k := 1; k < len(a); k++ {
+			s[k] = Div(weightedSum(a, c, k), Num(float64(k))).execute()
+			c[k] = Div(weightedSum(a, s, k), Num(float64(-k))).execute()
+		}
+		if name == "cos" {
+			return c, nil
+		}
+	case "ln":
+		if isZero(a[0]) || a[0].op == "num" && a[0].imaginary == 0 && a[0].number < 0 {
+			return nil, errSingular
+		}
+		s[0] = Ln(a[0]).execute()
+		for k := 1; k < len(a); k++ {
+			terms := []value{a[k]}
+			for j := 1; j < k; j++ {
+				terms = append(terms, Mul(Num(-float64(j)/float64(k)), Mul(s[j], a[k-j])))
+			}
+			s[k] = Div(sum(terms), a[0]).execute()
+		}
+	case "abs":
+		if a[0].op != "num" || a[0].imaginary != 0 || a[0].number == 0 {
+			return nil, errSingular
+		}
+		for k := range a {
+			s[k] = a[k]
+			if a[0].number < 0 {
+				s[k] = Mul(Num(-1), a[k]).execute()
+			}
+		}
+	}
+	return s, nil
+}
+
+func weightedSum(a, s []value, k int) value {
+	terms := make([]value, 0, k)
+	for j := 1; j <= k; j++ {
+		terms = append(terms, Mul(Num(float64(j)), Mul(a[j], s[k-j])))
+	}
+	return sum(terms)
+}
+
+func Linearize(expr value, point map[string]float64, order int) (*Expansion, error) {
+	if order != 1 && order != 2 {
+		return nil, errors.New("linearization supports first and second order only")
+	}
+	if len(point) == 0 {
+		return nil, errors.New("no expansion point given")
+	}
+
+	names := make([]string, 0, len(point))
+	for name := range point {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	substitutions := make(map[string]value, len(point))
+	for name, x := range point {
+		substitutions[name] = Num(x)
+	}
+	at := func(val value) (value, error) {
+		c := Substitute(val, substitutions).execute()
+		if c.op == "num" && !isFinite(c.number) {
+			return value{}, fmt.Errorf("%v is not analytic at %v", expr, point)
+		}
+		return c, nil
+	}
+
+	constant, err := at(expr)
+	if err != nil {
+		return nil, err
+	}
+	terms := []value{constant}
+	for i, name := range names {
+		gradient, err := Derive(expr, name)
+		if err != nil {
+			return nil, err
+		}
+		c, err := at(gradient)
+		if err != nil {
+			return nil, err
+		}
+		if !isZero(c) {
+			terms = append(terms, monomialTerm(c, []value{offset(name, point[name])}))
+		}
+		if order == 1 {
+			continue
+		}
+
+		for _, other := range names[i:] {
+			curvature, err := Derive(gradient, other)
+			if err != nil {
+				return nil, err
+			}
+			c, err := at(curvature)
+			if err != nil {
+				return nil, err
+			}
+			if isZero(c) {
+				continue
+			}
+			if other == name {
+				terms = append(terms, monomialTerm(Div(c, Num(2)).execute(), []value{power(offset(name, point[name]), 2)}))
+			} else {
+				terms = append(terms, monomialTerm(c, []value{offset(name, point[name]), offset(other, point[other])}))
+			}
+		}
+	}
+	if len(terms) > 1 && isZero(terms[0]) {
+		terms = terms[1:]
+	}
+	return &Expansion{sum(terms), point, order}, nil
+}
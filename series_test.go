@@ -0,0 +1,112 @@
+package equations_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gossie/equations"
+)
+
+func TestSeries(t *testing.T) {
+	expansion, err := equations.Series(equations.Exp(equations.Var(1, "x", 1)), "x", 0, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "(((1.000000 + 1.000000x) + 0.500000x^2) + 0.166667x^3) + O(1.000000x^4)"
+	if expansion.String() != expected {
+		t.Fatalf("expected %v to be %v", expansion, expected)
+	}
+}
+
+func TestSeries_aroundPoint(t *testing.T) {
+	expansion, err := equations.Series(equations.Ln(equations.Var(1, "x", 1)), "x", 1, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expected := "((1.000000x - 1.000000) + (-0.500000 * ((1.000000x - 1.000000) ^ 2.000000))) + O((1.000000x - 1.000000)^3)"
+	if expansion.String() != expected {
+		t.Fatalf("expected %v to be %v", expansion, expected)
+	}
+}
+
+func TestSeries_symbolicCoefficients(t *testing.T) {
+	expr := equations.Sin(equations.Mul(equations.Var(1, "a", 1), equations.Var(1, "x", 1)))
+
+	expansion, err := equations.Series(expr, "x", 0, 3)
+	if err != nil {
+		t.Fatal(err)
+	}
+	approximation, _ := equations.Evaluate(expansion.Polynomial, map[string]float64{"a": 2, "x": 0.01})
+	if math.Abs(approximation-math.Sin(0.02)) > 1e-9 {
+		t.Fatalf("expected %v to approximate sin(0.02)", approximation)
+	}
+}
+
+func TestSeries_notAnalytic(t *testing.T) {
+	if _, err := equations.Series(equations.Ln(equations.Var(1, "x", 1)), "x", 0, 2); err == nil {
+		t.Fatal("expected ln(x) not to expand around 0")
+	}
+}
+
+func TestLinearize(t *testing.T) {
+	expr := equations.Mul(equations.Var(1, "x", 2), equations.Exp(equations.Var(1, "y", 1)))
+	point := map[string]float64{"x": 1, "y": 0}
+
+	first, err := equations.Linearize(expr, point, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "((1.000000 + (2.000000 * (1.000000x - 1.000000))) + 1.000000y) + O(|(1.000000x - 1.000000), 1.000000y|^2)"
+	if first.String() != expected {
+		t.Fatalf("expected %v to be %v", first, expected)
+	}
+
+	second, err := equations.Linearize(expr, point, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	near := map[string]float64{"x": 1.01, "y": 0.02}
+	exact, _ := equations.Evaluate(expr, near)
+	linear, _ := equations.Evaluate(first.Polynomial, near)
+	quadratic, _ := equations.Evaluate(second.Polynomial, near)
+	if math.Abs(quadratic-exact) >= math.Abs(linear-exact) || math.Abs(quadratic-exact) > 1e-5 {
+		t.Fatalf("expected the second order %v to improve on %v for %v", quadratic, linear, exact)
+	}
+}
+
+func TestSeries_highOrder(t *testing.T) {
+	x := equations.Var(1, "x", 1)
+
+	secant, err := equations.Series(equations.Div(equations.Num(1), equations.Cos(x)), "x", 0, 8)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := "((((1.000000 + 0.500000x^2) + 0.208333x^4) + 0.084722x^6) + 0.034350x^8) + O(1.000000x^9)"
+	if secant.String() != expected {
+		t.Fatalf("expected %v to be %v", secant, expected)
+	}
+
+	composed, err := equations.Series(equations.Exp(equations.Sin(x)), "x", 0, 6)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected = "(((((1.000000 + 1.000000x) + 0.500000x^2) + -0.125000x^4) + -0.066667x^5) + -0.004167x^6) + O(1.000000x^7)"
+	if composed.String() != expected {
+		t.Fatalf("expected %v to be %v", composed, expected)
+	}
+}
+
+func TestLinearize_symbolicParameters(t *testing.T) {
+	expr := equations.Mul(equations.Var(1, "a", 1), equations.Exp(equations.Var(1, "x", 1)))
+
+	linearization, err := equations.Linearize(expr, map[string]float64{"x": 0}, 2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	series, _ := equations.Series(expr, "x", 0, 2)
+	if linearization.String() != series.String() {
+		t.Fatalf("expected %v to match %v", linearization, series)
+	}
+}
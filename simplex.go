@@ -0,0 +1,532 @@
+package equations
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"sort"
+	"strings"
+)
+
+type Objective int
+
+const (
+	Maximize Objective = iota
+	Minimize
+)
+
+type LPStatus int
+
+const (
+	Optimal LPStatus = iota
+	Infeasible
+	Unbounded
+)
+
+type LPOptions struct {
+	Exact bool
+	Free  []string
+}
+
+type LPResult struct {
+	Status     LPStatus
+	Value      float64
+	Point      map[string]float64
+	ExactValue *big.Rat
+	ExactPoint map[string]*big.Rat
+}
+
+func (r *LPResult) String() string {
+	switch r.Status {
+	default:
+		panic(fmt.Sprintf("unknown status %d", r.Status))
+	case Infeasible:
+		return "infeasible"
+	case Unbounded:
+		return "unbounded"
+	case Optimal:
+		names := make([]string, 0, len(r.Point))
+		for name := range r.Point {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		assignments := make([]string, len(names))
+		for i, name := range names {
+			assignments[i] = fmt.Sprintf("%v = %g", name, r.Point[name])
+		}
+		return fmt.Sprintf("optimal %g at (%v)", r.Value, strings.Join(assignments, ", "))
+	}
+}
+
+func LessOrEqual(left, right value) Condition {
+	return Condition{left: left, right: right, relation: "<="}
+}
+
+func GreaterOrEqual(left, right value) Condition {
+	return Condition{left: left, right: right, relation: ">="}
+}
+
+func EqualTo(left, right value) Condition {
+	return Condition{left: left, right: right, relation: "="}
+}
+
+type linearForm struct {
+	coefficients map[string]*big.Rat
+	constant     *big.Rat
+}
+
+func toLinearForm(val value) (linearForm, error) {
+	p, err := toPolynomial(SubstituteConstants(val))
+	if err != nil {
+		return linearForm{}, err
+	}
+	form := linearForm{make(map[string]*big.Rat), new(big.Rat)}
+	for _, t := range p.terms {
+		switch t.monomial.degree() {
+		case 0:
+			form.constant.Add(form.constant, t.coefficient)
+		case 1:
+			for name := range t.monomial {
+				form.coefficients[name] = t.coefficient
+			}
+		default:
+			return linearForm{}, errors.New(val.String() + " is not linear")
+		}
+	}
+	return form, nil
+}
+
+type tableau interface {
+	sign(i, j int) int
+	compareRatios(i, k, column int) int
+	pivot(row, column int)
+	float(i, j int) float64
+}
+
+type floatTableau [][]float64
+
+const simplexTolerance = 1e-9
+
+func (t floatTableau) sign(i, j int) int {
+	switch {
+	case t[i][j] > simplexTolerance:
+		return 1
+	case t[i][j] < -simplexTolerance:
+		return -1
+	}
+	return 0
+}
+
+func (t floatTableau) compareRatios(i, k, column int) int {
+	last := len(t[i]) - 1
+	a, b := t[i][last]/t[i][column], t[k][last]/t[k][column]
+	tolerance := simplexTolerance * math.Max(1, math.Max(math.Abs(a), math.Abs(b)))
+	switch {
+	case a-b > tolerance:
+		return 1
+	case a-b < -tolerance:
+		return -1
+	}
+	return 0
+}
+
+// newFloatTableau divides every constraint by its largest structural
+// coefficient so that the fixed tolerance is relative to the row's magnitude.
+func newFloatTableau(matrix [][]*big.Rat, structural int) floatTableau {
+	t := make(floatTableau, len(matrix))
+	for i, row := range matrix {
+		scale := largest(row[:structural])
+		t[i] = make([]float64, len(row))
+		for j, c := range row {
+			if scale.Sign() != 0 {
+				c = new(big.Rat).Quo(c, scale)
+			}
+			t[i][j], _ = c.Float64()
+		}
+	}
+	return t
+}
+
+func largest(row []*big.Rat) *big.Rat {
+	result := new(big.Rat)
+	for _, c := range row {
+		if abs := new(big.Rat).Abs(c); abs.Cmp(result) > 0 {
+			result = abs
+		}
+	}
+	return result
+}
+
+func (t floatTableau) pivot(row, column int) {
+	p := t[row][column]
+	for j := range t[row] {
+		t[row][j] /= p
+	}
+	for i := range t {
+		if i == row || t[i][column] == 0 {
+			continue
+		}
+		f := t[i][column]
+		for j := range t[i] {
+			t[i][j] -= f * t[row][j]
+		}
+	}
+}
+
+func (t floatTableau) float(i, j int) float64 {
+	return t[i][j]
+}
+
+type ratTableau [][]*big.Rat
+
+func (t ratTableau) sign(i, j int) int {
+	return t[i][j].Sign()
+}
+
+func (t ratTableau) compareRatios(i, k, column int) int {
+	last := len(t[i]) - 1
+	a := new(big.Rat).Quo(t[i][last], t[i][column])
+	b := new(big.Rat).Quo(t[k][last], t[k][column])
+	return a.Cmp(b)
+}
+
+func (t ratTableau) pivot(row, column int) {
+	p := new(big.Rat).Set(t[row][column])
+	for j := range t[row] {
+		t[row][j].Quo(t[row][j], p)
+	}
+	for i := range t {
+		if i == row || t[i][column].Sign() == 0 {
+			continue
+		}
+		f := new(big.Rat).Set(t[i][column])
+		for j := range t[i] {
+			t[i][j].Sub(t[i][j], new(big.Rat).Mul(f, t[row][j]))
+		}
+	}
+}
+
+func (t ratTableau) float(i, j int) float64 {
+	f, _ := t[i][j].Float64()
+	return f
+}
+
+type simplex struct {
+	t        tableau
+	rows     int
+	columns  int
+	basis    []int
+	eligible func(column int) bool
+}
+
+func (s *simplex) solve() bool {
+	objective := s.rows
+	for {
+		entering := -1
+		for j := 0; j < s.columns; j++ {
+			if s.eligible(j) && s.t.sign(objective, j) < 0 {
+				entering = j
+				break
+			}
+		}
+		if entering < 0 {
+			return true
+		}
+
+		leaving := -1
+		for i := 0; i < s.rows; i++ {
+			if s.t.sign(i, entering) <= 0 {
+				continue
+			}
+			if leaving < 0 {
+				leaving = i
+				continue
+			}
+			c := s.t.compareRatios(i, leaving, entering)
+			if c < 0 || (c == 0 && s.basis[i] < s.basis[leaving]) {
+				leaving = i
+			}
+		}
+		if leaving < 0 {
+			return false
+		}
+		s.t.pivot(leaving, entering)
+		s.basis[leaving] = entering
+	}
+}
+
+func LinearProgram(objective value, sense Objective, opts LPOptions, constraints ...Condition) (*LPResult, error) {
+	goal, err := toLinearForm(objective)
+	if err != nil {
+		return nil, err
+	}
+	if sense == Minimize {
+		for name, c := range goal.coefficients {
+			goal.coefficients[name] = new(big.Rat).Neg(c)
+		}
+	}
+
+	forms := make([]linearForm, len(constraints))
+	relations := make([]string, len(constraints))
+	known := make(map[string]bool)
+	for name := range goal.coefficients {
+		known[name] = true
+	}
+	for i, c := range constraints {
+		if c.relation != "<=" && c.relation != ">=" && c.relation != "=" {
+			return nil, fmt.Errorf("unsupported constraint %v", c)
+		}
+		forms[i], err = toLinearForm(Sub(c.left, c.right))
+		if err != nil {
+			return nil, err
+		}
+		relations[i] = c.relation
+		for name := range forms[i].coefficients {
+			known[name] = true
+		}
+	}
+
+	free := make(map[string]bool)
+	for _, name := range opts.Free {
+		free[name] = true
+	}
+	names := make([]string, 0, len(known))
+	for name := range known {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	columnOf := make(map[string]int)
+	structural := 0
+	for _, name := range names {
+		columnOf[name] = structural
+		structural++
+		if free[name] {
+			structural++
+		}
+	}
+
+	slacks, artificials := 0, 0
+	for i, form := range forms {
+		if form.constant.Sign() > 0 {
+			relations[i] = flip(relations[i])
+		}
+		if relations[i] != "=" {
+			slacks++
+		}
+		if relations[i] != "<=" {
+			artificials++
+		}
+	}
+
+	rows := len(forms)
+	columns := structural + slacks + artificials
+	matrix := make([][]*big.Rat, rows+1)
+	for i := range matrix {
+		matrix[i] = make([]*big.Rat, columns+1)
+		for j := range matrix[i] {
+			matrix[i][j] = new(big.Rat)
+		}
+	}
+
+	basis := make([]int, rows)
+	slack, artificial := structural, structural+slacks
+	for i, form := range forms {
+		negate := form.constant.Sign() > 0
+		set := func(j int, c *big.Rat) {
+			if negate {
+				c = new(big.Rat).Neg(c)
+			}
+			matrix[i][j].Add(matrix[i][j], c)
+		}
+		for name, c := range form.coefficients {
+			set(columnOf[name], c)
+			if free[name] {
+				set(columnOf[name]+1, new(big.Rat).Neg(c))
+			}
+		}
+		matrix[i][columns].Abs(form.constant)
+
+		switch relations[i] {
+		case "<=":
+			matrix[i][slack].SetInt64(1)
+			basis[i] = slack
+			slack++
+		case ">=":
+			matrix[i][slack].SetInt64(-1)
+			slack++
+			fallthrough
+		case "=":
+			matrix[i][artificial].SetInt64(1)
+			basis[i] = artificial
+			artificial++
+		}
+	}
+
+	var t tableau = ratTableau(matrix)
+	if !opts.Exact {
+		t = newFloatTableau(matrix, structural)
+	}
+	s := &simplex{t: t, rows: rows, columns: columns, basis: basis, eligible: func(int) bool { return true }}
+	isArtificial := func(j int) bool { return j >= structural+slacks }
+
+	if artificials > 0 {
+		setObjective(t, rows, columns, func(j int) *big.Rat {
+			if isArtificial(j) {
+				return big.NewRat(1, 1)
+			}
+			return new(big.Rat)
+		})
+		s.canonicalize()
+		s.solve()
+		if t.sign(rows, columns) < 0 {
+			return &LPResult{Status: Infeasible}, nil
+		}
+		s.removeArtificials(isArtificial)
+	}
+
+	s.eligible = func(j int) bool { return !isArtificial(j) }
+	setObjective(t, rows, columns, func(j int) *big.Rat {
+		for _, name := range names {
+			c, present := goal.coefficients[name]
+			if !present {
+				continue
+			}
+			if j == columnOf[name] {
+				return new(big.Rat).Neg(c)
+			}
+			if free[name] && j == columnOf[name]+1 {
+				return new(big.Rat).Set(c)
+			}
+		}
+		return new(big.Rat)
+	})
+	s.canonicalize()
+	if !s.solve() {
+		return &LPResult{Status: Unbounded}, nil
+	}
+
+	solution := make([]float64, columns)
+	for i, j := range basis {
+		solution[j] = t.float(i, columns)
+	}
+	point := make(map[string]float64, len(names))
+	for _, name := range names {
+		point[name] = solution[columnOf[name]]
+		if free[name] {
+			point[name] -= solution[columnOf[name]+1]
+		}
+	}
+	optimum, err := Evaluate(objective, point)
+	if err != nil {
+		return nil, err
+	}
+	result := &LPResult{Status: Optimal, Value: optimum, Point: point}
+	if rats, exact := t.(ratTableau); exact {
+		result.ExactPoint, result.ExactValue = exactOptimum(rats, basis, names, columnOf, free, objective)
+	}
+	return result, nil
+}
+
+func exactOptimum(t ratTableau, basis []int, names []string, columnOf map[string]int, free map[string]bool, objective value) (map[string]*big.Rat, *big.Rat) {
+	last := len(t[0]) - 1
+	solution := make(map[int]*big.Rat, len(basis))
+	for i, j := range basis {
+		solution[j] = t[i][last]
+	}
+	at := func(j int) *big.Rat {
+		if c, present := solution[j]; present {
+			return c
+		}
+		return new(big.Rat)
+	}
+
+	point := make(map[string]*big.Rat, len(names))
+	for _, name := range names {
+		point[name] = new(big.Rat).Set(at(columnOf[name]))
+		if free[name] {
+			point[name].Sub(point[name], at(columnOf[name]+1))
+		}
+	}
+
+	form, _ := toLinearForm(objective)
+	optimum := new(big.Rat).Set(form.constant)
+	for name, c := range form.coefficients {
+		optimum.Add(optimum, new(big.Rat).Mul(c, point[name]))
+	}
+	return point, optimum
+}
+
+func flip(relation string) string {
+	switch relation {
+	case "<=":
+		return ">="
+	case ">=":
+		return "<="
+	}
+	return relation
+}
+
+func setObjective(t tableau, row, columns int, cost func(j int) *big.Rat) {
+	switch t := t.(type) {
+	case ratTableau:
+		for j := 0; j <= columns; j++ {
+			if j == columns {
+				t[row][j] = new(big.Rat)
+			} else {
+				t[row][j] = cost(j)
+			}
+		}
+	case floatTableau:
+		costs := make([]*big.Rat, columns)
+		for j := range costs {
+			costs[j] = cost(j)
+		}
+		scale := largest(costs)
+		for j := 0; j <= columns; j++ {
+			t[row][j] = 0
+			if j < columns && scale.Sign() != 0 {
+				t[row][j], _ = new(big.Rat).Quo(costs[j], scale).Float64()
+			}
+		}
+	}
+}
+
+func (s *simplex) canonicalize() {
+	for i, j := range s.basis {
+		if s.t.sign(s.rows, j) != 0 {
+			s.eliminate(i, j)
+		}
+	}
+}
+
+func (s *simplex) eliminate(row, column int) {
+	switch t := s.t.(type) {
+	case ratTableau:
+		f := new(big.Rat).Set(t[s.rows][column])
+		for j := range t[s.rows] {
+			t[s.rows][j].Sub(t[s.rows][j], new(big.Rat).Mul(f, t[row][j]))
+		}
+	case floatTableau:
+		f := t[s.rows][column]
+		for j := range t[s.rows] {
+			t[s.rows][j] -= f * t[row][j]
+		}
+	}
+}
+
+func (s *simplex) removeArtificials(isArtificial func(int) bool) {
+	for i, j := range s.basis {
+		if !isArtificial(j) {
+			continue
+		}
+		for k := 0; k < s.columns; k++ {
+			if !isArtificial(k) && s.t.sign(i, k) != 0 {
+				s.t.pivot(i, k)
+				s.basis[i] = k
+				break
+			}
+		}
+	}
+}
@@ -0,0 +1,125 @@
+package equations_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gossie/equations"
+)
+
+func assertOptimum(t *testing.T, result *equations.LPResult, err error, value float64, point map[string]float64) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Status != equations.Optimal || math.Abs(result.Value-value) > 1e-9 {
+		t.Fatalf("expected %v to be optimal with value %v", result, value)
+	}
+	for name, expected := range point {
+		if math.Abs(result.Point[name]-expected) > 1e-9 {
+			t.Fatalf("expected %v = %v in %v", name, expected, result)
+		}
+	}
+}
+
+func TestLinearProgram_maximize(t *testing.T) {
+	x, y := equations.Var(1, "x", 1), equations.Var(1, "y", 1)
+	objective := equations.Add(equations.Var(3, "x", 1), equations.Var(2, "y", 1))
+	constraints := []equations.Condition{
+		equations.LessOrEqual(equations.Add(x, y), equations.Num(4)),
+		equations.LessOrEqual(equations.Add(x, equations.Var(3, "y", 1)), equations.Num(6)),
+		equations.LessOrEqual(x, equations.Num(3)),
+	}
+
+	for _, exact := range []bool{false, true} {
+		result, err := equations.LinearProgram(objective, equations.Maximize, equations.LPOptions{Exact: exact}, constraints...)
+		assertOptimum(t, result, err, 11, map[string]float64{"x": 3, "y": 1})
+	}
+
+	result, _ := equations.LinearProgram(objective, equations.Maximize, equations.LPOptions{}, constraints...)
+	if result.String() != "optimal 11 at (x = 3, y = 1)" {
+		t.Fatalf("unexpected result %v", result)
+	}
+}
+
+func TestLinearProgram_minimizeWithPhaseOne(t *testing.T) {
+	x, y := equations.Var(1, "x", 1), equations.Var(1, "y", 1)
+
+	result, err := equations.LinearProgram(equations.Add(x, y), equations.Minimize, equations.LPOptions{Exact: true},
+		equations.GreaterOrEqual(equations.Add(x, equations.Var(2, "y", 1)), equations.Num(4)),
+		equations.GreaterOrEqual(equations.Var(3, "x", 1), equations.Sub(equations.Num(6), y)),
+		equations.EqualTo(x, y))
+	assertOptimum(t, result, err, 3, map[string]float64{"x": 1.5, "y": 1.5})
+}
+
+func TestLinearProgram_freeVariables(t *testing.T) {
+	x := equations.Var(1, "x", 1)
+
+	result, err := equations.LinearProgram(x, equations.Minimize, equations.LPOptions{Free: []string{"x"}}, equations.GreaterOrEqual(x, equations.Num(-2)))
+	assertOptimum(t, result, err, -2, map[string]float64{"x": -2})
+}
+
+func TestLinearProgram_degenerateWithoutCycling(t *testing.T) {
+	objective := equations.Add(equations.Add(equations.Var(0.75, "a", 1), equations.Var(-20, "b", 1)), equations.Add(equations.Var(0.5, "c", 1), equations.Var(-6, "d", 1)))
+	constraints := []equations.Condition{
+		equations.LessOrEqual(equations.Add(equations.Add(equations.Var(0.25, "a", 1), equations.Var(-8, "b", 1)), equations.Add(equations.Var(-1, "c", 1), equations.Var(9, "d", 1))), equations.Num(0)),
+		equations.LessOrEqual(equations.Add(equations.Add(equations.Var(0.5, "a", 1), equations.Var(-12, "b", 1)), equations.Add(equations.Var(-0.5, "c", 1), equations.Var(3, "d", 1))), equations.Num(0)),
+		equations.LessOrEqual(equations.Var(1, "c", 1), equations.Num(1)),
+	}
+
+	for _, exact := range []bool{false, true} {
+		result, err := equations.LinearProgram(objective, equations.Maximize, equations.LPOptions{Exact: exact}, constraints...)
+		assertOptimum(t, result, err, 1.25, map[string]float64{"a": 1, "c": 1})
+	}
+}
+
+func TestLinearProgram_unboundedAndInfeasible(t *testing.T) {
+	x := equations.Var(1, "x", 1)
+
+	result, err := equations.LinearProgram(x, equations.Maximize, equations.LPOptions{}, equations.GreaterOrEqual(x, equations.Num(1)))
+	if err != nil || result.Status != equations.Unbounded {
+		t.Fatalf("expected %v to be unbounded (%v)", result, err)
+	}
+
+	result, err = equations.LinearProgram(x, equations.Maximize, equations.LPOptions{}, equations.GreaterOrEqual(x, equations.Num(2)), equations.LessOrEqual(x, equations.Num(1)))
+	if err != nil || result.Status != equations.Infeasible {
+		t.Fatalf("expected %v to be infeasible (%v)", result, err)
+	}
+}
+
+func TestLinearProgram_nonLinear(t *testing.T) {
+	if _, err := equations.LinearProgram(equations.Var(1, "x", 2), equations.Maximize, equations.LPOptions{}); err == nil {
+		t.Fatal("expected an error for a quadratic objective")
+	}
+}
+
+func TestLinearProgram_badlyScaled(t *testing.T) {
+	x := equations.Var(1, "x", 1)
+
+	result, err := equations.LinearProgram(x, equations.Maximize, equations.LPOptions{}, equations.LessOrEqual(equations.Var(1e-10, "x", 1), equations.Num(1)))
+	if err != nil || result.Status != equations.Optimal || math.Abs(result.Value-1e10) > 1e-9*1e10 {
+		t.Fatalf("expected %v to be optimal at 1e10 (%v)", result, err)
+	}
+
+	result, err = equations.LinearProgram(equations.Var(1e-12, "x", 1), equations.Maximize, equations.LPOptions{}, equations.LessOrEqual(x, equations.Num(2)))
+	assertOptimum(t, result, err, 2e-12, map[string]float64{"x": 2})
+}
+
+func TestLinearProgram_exactResult(t *testing.T) {
+	x, y := equations.Var(1, "x", 1), equations.Var(1, "y", 1)
+
+	result, err := equations.LinearProgram(equations.Add(x, y), equations.Maximize, equations.LPOptions{Exact: true},
+		equations.LessOrEqual(equations.Var(3, "x", 1), equations.Num(1)),
+		equations.LessOrEqual(equations.Var(3, "y", 1), equations.Num(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.ExactValue.String() != "2/3" || result.ExactPoint["x"].String() != "1/3" || result.ExactPoint["y"].String() != "1/3" {
+		t.Fatalf("expected an exact optimum of 2/3 at (1/3, 1/3), got %v at %v", result.ExactValue, result.ExactPoint)
+	}
+
+	result, _ = equations.LinearProgram(x, equations.Maximize, equations.LPOptions{}, equations.LessOrEqual(x, equations.Num(1)))
+	if result.ExactValue != nil || result.ExactPoint != nil {
+		t.Fatalf("expected no exact result without the Exact option, got %v", result.ExactValue)
+	}
+}
@@ -0,0 +1,362 @@
+package equations
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+type SolutionKind int
+
+const (
+	EmptySet SolutionKind = iota
+	AllReals
+	FiniteSet
+	ParametricFamily
+	AllIntegers
+)
+
+type Condition struct {
+	left, right value
+	relation    string
+}
+
+func (c Condition) String() string {
+	return fmt.Sprintf("%v %v %v", c.left, c.relation, c.right)
+}
+
+func notZero(val value) Condition {
+	return Condition{left: val, right: Num(0), relation: "!="}
+}
+
+func notNegative(val value) Condition {
+	return Condition{left: val, right: Num(0), relation: ">="}
+}
+
+type SolutionSet struct {
+	Kind       SolutionKind
+	Variables  []string
+	Values     []value
+	Parameters []string
+	Conditions []Condition
+}
+
+func (s *SolutionSet) String() string {
+	var result string
+	switch s.Kind {
+	default:
+		panic(fmt.Sprintf("unknown solution kind %d", s.Kind))
+	case EmptySet:
+		result = "{}"
+	case AllReals:
+		result = "R"
+	case FiniteSet:
+		result = "{" + s.joinValues() + "}"
+	case ParametricFamily:
+		result = "{" + s.joinValues() + " : " + strings.Join(s.Parameters, ", ") + "}"
+	case AllIntegers:
+		result = "Z"
+	}
+
+	if len(s.Conditions) > 0 {
+		conditions := make([]string, 0, len(s.Conditions))
+		for _, c := range s.Conditions {
+			conditions = append(conditions, c.String())
+		}
+		result += " if " + strings.Join(conditions, " and ")
+	}
+	return result
+}
+
+func (s *SolutionSet) joinValues() string {
+	if len(s.Variables) != len(s.Values) {
+		return joinValues(s.Values)
+	}
+	strs := make([]string, 0, len(s.Values))
+	for i, v := range s.Values {
+		strs = append(strs, s.Variables[i]+" = "+v.String())
+	}
+	return strings.Join(strs, ", ")
+}
+
+func joinValues(values []value) string {
+	strs := make([]string, 0, len(values))
+	for _, v := range values {
+		strs = append(strs, v.String())
+	}
+	return strings.Join(strs, ", ")
+}
+
+const maxEliminationSteps = 20
+
+func SolveSetTo(eq *equation, varName string) (*SolutionSet, error) {
+	conditions := make([]Condition, 0)
+	current := *eq
+	for i := 0; i < maxEliminationSteps; i++ {
+		if identity(&current) {
+			return &SolutionSet{Kind: AllReals, Conditions: conditions}, nil
+		}
+
+		left, _, leftComplementaryPath, errLeft := findValue(&current.left, varName)
+		right, rightPath, rightComplementaryPath, errRight := findValue(&current.right, varName)
+
+		if left != nil && right != nil {
+			var next equation
+			if len(rightPath) == 0 || !rightPath[len(rightPath)-1].eliminates() {
+				next = NewEquation(Sub(current.left, current.right), Num(0))
+			} else {
+				element := rightPath[len(rightPath)-1]
+				conditions = appendDomainCondition(conditions, element, current.right)
+				next = NewEquation(processPathElement(element, current.left), processPathElement(element, current.right))
+			}
+			current = optimize(&next)
+			continue
+		}
+
+		if errLeft != nil && errRight != nil {
+			if err := blockingError(errLeft, errRight); err != nil {
+				return nil, &SolveError{err, &current}
+			}
+			return withoutVariable(&current, conditions), nil
+		}
+
+		found, other, complementaryPath := left, current.right, leftComplementaryPath
+		if left == nil {
+			found, other, complementaryPath = right, current.left, rightComplementaryPath
+		}
+
+		solutions := &SolutionSet{Kind: EmptySet}
+		for _, b := range processPathBranches(other, complementaryPath) {
+			if b.degenerate != nil {
+				solutions = union(solutions, b.degenerate)
+				continue
+			}
+			if containsVariable(b.current, varName) {
+				return nil, &SolveError{errors.New(varName + " could not be isolated"), &current}
+			}
+			solutions = union(solutions, solvePower(b.current, found.exponent, append(append([]Condition(nil), conditions...), b.conditions...)))
+		}
+		return solutions, nil
+	}
+	return nil, &SolveError{errors.New(varName + " could not be eliminated from both sides"), &current}
+}
+
+type branch struct {
+	current    value
+	conditions []Condition
+	degenerate *SolutionSet
+}
+
+func identity(eq *equation) bool {
+	if Equal(eq.left, eq.right) {
+		return true
+	}
+	difference := Sub(eq.left, eq.right)
+	if p, err := toPolynomial(difference); err == nil {
+		return p.isZero()
+	}
+	return isZero(difference.execute())
+}
+
+// degenerate handles a division by a zero coefficient or a zero quotient,
+// where the equation no longer depends on the variable at all.
+func degenerate(v *opValuePair, b branch) *SolutionSet {
+	if v.op != "/" {
+		return nil
+	}
+	switch {
+	case !v.swap && isZero(v.val.execute()):
+		eq := NewEquation(b.current, Num(0))
+		return withoutVariable(&eq, b.conditions)
+	case v.swap && isZero(b.current.execute()):
+		eq := NewEquation(v.val, Num(0))
+		return withoutVariable(&eq, b.conditions)
+	}
+	return nil
+}
+
+func processPathBranches(val value, p path) []branch {
+	branches := []branch{{current: val}}
+	for i := len(p) - 1; i >= 0; i-- {
+		next := make([]branch, 0, len(branches))
+		for _, b := range branches {
+			if b.degenerate != nil {
+				next = append(next, b)
+				continue
+			}
+			if set := degenerate(p[i], b); set != nil {
+				next = append(next, branch{degenerate: set})
+				continue
+			}
+			if p[i].op == "root" {
+				next = append(next, rootBranches(p[i].val, b)...)
+				continue
+			}
+			if !inDomain(p[i], b.current) {
+				continue
+			}
+			conditions := appendDomainCondition(b.conditions, p[i], b.current)
+			next = append(next, branch{current: processPathElement(p[i], b.current), conditions: conditions})
+		}
+		branches = next
+	}
+	for i := range branches {
+		if branches[i].degenerate == nil {
+			branches[i].current = branches[i].current.execute()
+		}
+	}
+	return branches
+}
+
+func rootBranches(exponent value, b branch) []branch {
+	exponent = exponent.execute()
+	argument := b.current.execute()
+	if exponent.op != "num" || exponent.imaginary != 0 {
+		return []branch{{current: Pow(argument, Div(Num(1), exponent)), conditions: append(b.conditions, notNegative(argument))}}
+	}
+
+	roots := solvePower(argument, exponent.number, b.conditions)
+	branches := make([]branch, 0, len(roots.Values))
+	for _, root := range roots.Values {
+		branches = append(branches, branch{current: root, conditions: roots.Conditions})
+	}
+	return branches
+}
+
+func union(a, b *SolutionSet) *SolutionSet {
+	if b.Kind == EmptySet || a.Kind == AllReals {
+		return a
+	}
+	if a.Kind == EmptySet || b.Kind == AllReals {
+		return b
+	}
+
+	result := &SolutionSet{Kind: FiniteSet, Values: append([]value(nil), a.Values...), Conditions: append([]Condition(nil), a.Conditions...)}
+	for _, v := range b.Values {
+		if !containsValue(result.Values, v) {
+			result.Values = append(result.Values, v)
+		}
+	}
+	for _, c := range b.Conditions {
+		if !containsCondition(result.Conditions, c) {
+			result.Conditions = append(result.Conditions, c)
+		}
+	}
+	return result
+}
+
+func containsValue(values []value, v value) bool {
+	for _, other := range values {
+		if Equal(other, v) {
+			return true
+		}
+	}
+	return false
+}
+
+func containsCondition(conditions []Condition, c Condition) bool {
+	for _, other := range conditions {
+		if other.relation == c.relation && Equal(other.left, c.left) && Equal(other.right, c.right) {
+			return true
+		}
+	}
+	return false
+}
+
+func withoutVariable(eq *equation, conditions []Condition) *SolutionSet {
+	l := eq.left.execute()
+	r := eq.right.execute()
+	if l.op == "num" && r.op == "num" {
+		if l.number == r.number && l.imaginary == r.imaginary {
+			return &SolutionSet{Kind: AllReals, Conditions: conditions}
+		}
+		return &SolutionSet{Kind: EmptySet}
+	}
+	return &SolutionSet{Kind: AllReals, Conditions: append(conditions, Condition{left: l, right: r, relation: "="})}
+}
+
+func solvePower(result value, exponent float64, conditions []Condition) *SolutionSet {
+	if exponent == 1 {
+		return &SolutionSet{Kind: FiniteSet, Values: []value{result}, Conditions: conditions}
+	}
+
+	even := math.Mod(exponent, 2) == 0
+	integer := exponent == math.Trunc(exponent)
+
+	if result.op == "num" {
+		r := result.number
+		switch {
+		case r == 0 && exponent < 0:
+			return &SolutionSet{Kind: EmptySet}
+		case r == 0:
+			return &SolutionSet{Kind: FiniteSet, Values: []value{Num(0)}, Conditions: conditions}
+		case r < 0 && (even || !integer):
+			return &SolutionSet{Kind: EmptySet}
+		case r < 0:
+			return &SolutionSet{Kind: FiniteSet, Values: []value{Num(-math.Pow(-r, 1/exponent))}, Conditions: conditions}
+		case even:
+			root := math.Pow(r, 1/exponent)
+			return &SolutionSet{Kind: FiniteSet, Values: []value{Num(root), Num(-root)}, Conditions: conditions}
+		default:
+			return &SolutionSet{Kind: FiniteSet, Values: []value{Num(math.Pow(r, 1/exponent))}, Conditions: conditions}
+		}
+	}
+
+	if exponent < 0 {
+		conditions = append(conditions, notZero(result))
+	}
+	root := Pow(result, Num(1/exponent)).execute()
+	if even {
+		conditions = append(conditions, notNegative(result))
+		return &SolutionSet{Kind: FiniteSet, Values: []value{root, Mul(Num(-1), root).execute()}, Conditions: conditions}
+	}
+	if !integer {
+		conditions = append(conditions, notNegative(result))
+	}
+	return &SolutionSet{Kind: FiniteSet, Values: []value{root}, Conditions: conditions}
+}
+
+func inDomain(v *opValuePair, current value) bool {
+	if v.op != "ln" && v.op != "log" {
+		return true
+	}
+	argument := current.execute()
+	return argument.op != "num" || (argument.imaginary == 0 && argument.number > 0)
+}
+
+func appendDomainCondition(conditions []Condition, v *opValuePair, current value) []Condition {
+	if v.op == "ln" || v.op == "log" {
+		argument := current.execute()
+		if argument.op == "num" {
+			return conditions
+		}
+		return append(conditions, Condition{left: argument, right: Num(0), relation: ">"})
+	}
+
+	if v.op != "/" {
+		return conditions
+	}
+
+	// A swapped division v.val / current stands for the original divisor,
+	// so its numerator must not vanish either.
+	divisors := []value{v.val}
+	if v.swap {
+		divisors = []value{current, v.val}
+	}
+	for _, divisor := range divisors {
+		divisor = divisor.execute()
+		if divisor.op != "num" {
+			conditions = append(conditions, notZero(divisor))
+		}
+	}
+	return conditions
+}
+
+func containsVariable(val value, name string) bool {
+	if variable(name)(&val) {
+		return true
+	}
+	return val.left != nil && containsVariable(*val.left, name) ||
+		val.right != nil && containsVariable(*val.right, name)
+}
@@ -0,0 +1,224 @@
+package equations_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gossie/equations"
+)
+
+func TestSolveSetTo_finite(t *testing.T) {
+	left := equations.Add(equations.Var(4, "r", 1), equations.Mul(equations.Num(0), equations.Num(7)))
+	right := equations.Add(equations.Var(1, "s", 1), equations.Div(equations.Num(25), equations.Num(5)))
+
+	eq := equations.NewEquation(left, right)
+	solutions, err := equations.SolveSetTo(&eq, "r")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if solutions.Kind != equations.FiniteSet || solutions.String() != "{(0.250000s + 1.250000)}" {
+		t.Fatalf("expected %v to be {(0.250000s + 1.250000)}", solutions)
+	}
+}
+
+func TestSolveSetTo_empty(t *testing.T) {
+	left := equations.Add(equations.Var(2, "x", 1), equations.Num(1))
+	right := equations.Add(equations.Var(2, "x", 1), equations.Num(3))
+
+	eq := equations.NewEquation(left, right)
+	solutions, err := equations.SolveSetTo(&eq, "x")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if solutions.Kind != equations.EmptySet {
+		t.Fatalf("expected %v to be empty", solutions)
+	}
+}
+
+func TestSolveSetTo_identity(t *testing.T) {
+	left := equations.Add(equations.Var(2, "x", 1), equations.Num(3))
+	right := equations.Add(equations.Var(2, "x", 1), equations.Num(3))
+
+	eq := equations.NewEquation(left, right)
+	solutions, err := equations.SolveSetTo(&eq, "x")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if solutions.Kind != equations.AllReals || solutions.String() != "R" {
+		t.Fatalf("expected %v to be R", solutions)
+	}
+}
+
+func TestSolveSetTo_divisionByVariable(t *testing.T) {
+	eq := equations.NewEquation(equations.Mul(equations.Var(1, "x", 1), equations.Var(1, "s", 1)), equations.Num(4))
+	solutions, err := equations.SolveSetTo(&eq, "x")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if solutions.String() != "{4.000000s^-1} if 1.000000s != 0.000000" {
+		t.Fatalf("expected %v to be {4.000000s^-1} if 1.000000s != 0.000000", solutions)
+	}
+}
+
+func TestSolveSetTo_square(t *testing.T) {
+	eq := equations.NewEquation(equations.Var(2, "x", 2), equations.Num(8))
+	solutions, err := equations.SolveSetTo(&eq, "x")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if solutions.String() != "{2.000000, -2.000000}" {
+		t.Fatalf("expected %v to be {2.000000, -2.000000}", solutions)
+	}
+}
+
+func TestSolveSetTo_squareOfNegative(t *testing.T) {
+	eq := equations.NewEquation(equations.Var(1, "x", 2), equations.Num(-1))
+	solutions, err := equations.SolveSetTo(&eq, "x")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if solutions.Kind != equations.EmptySet {
+		t.Fatalf("expected %v to be empty", solutions)
+	}
+}
+
+func TestSolveSetTo_variableMissing(t *testing.T) {
+	eq := equations.NewEquation(equations.Var(1, "y", 1), equations.Num(1))
+	solutions, err := equations.SolveSetTo(&eq, "x")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if solutions.Kind != equations.AllReals || solutions.String() != "R if 1.000000y = 1.000000" {
+		t.Fatalf("expected %v to be R if 1.000000y = 1.000000", solutions)
+	}
+}
+
+func TestSolveSetTo_power(t *testing.T) {
+	eq := equations.NewEquation(equations.Pow(equations.Add(equations.Var(1, "x", 1), equations.Num(1)), equations.Num(2)), equations.Num(9))
+	solutions, err := equations.SolveSetTo(&eq, "x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if solutions.String() != "{2.000000, -4.000000}" {
+		t.Fatalf("expected %v to be {2.000000, -4.000000}", solutions)
+	}
+
+	negative := equations.NewEquation(equations.Pow(equations.Add(equations.Var(1, "x", 1), equations.Num(1)), equations.Num(2)), equations.Num(-9))
+	solutions, err = equations.SolveSetTo(&negative, "x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if solutions.Kind != equations.EmptySet {
+		t.Fatalf("expected %v to be empty", solutions)
+	}
+}
+
+func TestSolveSetTo_exponent(t *testing.T) {
+	eq := equations.NewEquation(equations.Pow(equations.Num(2), equations.Var(1, "x", 1)), equations.Num(8))
+	solutions, err := equations.SolveSetTo(&eq, "x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if solutions.Kind != equations.FiniteSet || math.Abs(solutions.Values[0].Number()-3) > 1e-12 {
+		t.Fatalf("expected %v to be {3}", solutions)
+	}
+}
+
+func TestSolveTo_powerDoesNotPanic(t *testing.T) {
+	eq := equations.NewEquation(equations.Pow(equations.Add(equations.Var(1, "x", 1), equations.Num(1)), equations.Num(3)), equations.Num(8))
+	result, err := equations.SolveTo(&eq, "x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(result.Number()-1) > 1e-12 {
+		t.Fatalf("expected %v to be 1", result)
+	}
+}
+
+func TestSolveSetTo_notInvertible(t *testing.T) {
+	eq := equations.NewEquation(equations.Sin(equations.Var(1, "x", 1)), equations.Num(0.5))
+	if solutions, err := equations.SolveSetTo(&eq, "x"); err == nil {
+		t.Fatalf("expected an error, got %v", solutions)
+	}
+
+	mixed := equations.NewEquation(equations.Pow(equations.Var(1, "x", 1), equations.Var(1, "x", 1)), equations.Num(4))
+	if solutions, err := equations.SolveSetTo(&mixed, "x"); err == nil {
+		t.Fatalf("expected an error, got %v", solutions)
+	}
+}
+
+func TestSolveSetTo_outsideDomain(t *testing.T) {
+	eq := equations.NewEquation(equations.Exp(equations.Var(1, "x", 1)), equations.Num(-1))
+	solutions, err := equations.SolveSetTo(&eq, "x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if solutions.Kind != equations.EmptySet {
+		t.Fatalf("expected %v to be empty", solutions)
+	}
+
+	power := equations.NewEquation(equations.Pow(equations.Num(2), equations.Var(1, "x", 1)), equations.Num(0))
+	solutions, err = equations.SolveSetTo(&power, "x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if solutions.Kind != equations.EmptySet {
+		t.Fatalf("expected %v to be empty", solutions)
+	}
+}
+
+func assertSolutionSet(t *testing.T, solutions *equations.SolutionSet, err error, expected string) {
+	t.Helper()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if solutions.String() != expected {
+		t.Fatalf("expected %v to be %v", solutions, expected)
+	}
+}
+
+func TestSolveSetTo_zeroCoefficient(t *testing.T) {
+	x := equations.Var(1, "x", 1)
+
+	eq := equations.NewEquation(equations.Var(0, "x", 1), equations.Num(5))
+	solutions, err := equations.SolveSetTo(&eq, "x")
+	assertSolutionSet(t, solutions, err, "{}")
+
+	eq = equations.NewEquation(equations.Mul(x, equations.Num(0)), equations.Num(5))
+	solutions, err = equations.SolveSetTo(&eq, "x")
+	assertSolutionSet(t, solutions, err, "{}")
+
+	eq = equations.NewEquation(equations.Mul(x, equations.Num(0)), equations.Num(0))
+	solutions, err = equations.SolveSetTo(&eq, "x")
+	assertSolutionSet(t, solutions, err, "R")
+
+	eq = equations.NewEquation(equations.Mul(x, equations.Num(0)), equations.Var(1, "a", 1))
+	solutions, err = equations.SolveSetTo(&eq, "x")
+	assertSolutionSet(t, solutions, err, "R if 1.000000a = 0.000000")
+}
+
+func TestSolveSetTo_zeroQuotient(t *testing.T) {
+	eq := equations.NewEquation(equations.Div(equations.Num(1), equations.Var(1, "x", 1)), equations.Num(0))
+	solutions, err := equations.SolveSetTo(&eq, "x")
+	assertSolutionSet(t, solutions, err, "{}")
+}
+
+func TestSolveSetTo_swappedDivisor(t *testing.T) {
+	eq := equations.NewEquation(equations.Div(equations.Var(1, "s", 1), equations.Var(1, "x", 1)), equations.Num(2))
+	solutions, err := equations.SolveSetTo(&eq, "x")
+	assertSolutionSet(t, solutions, err, "{0.500000s} if 1.000000s != 0.000000")
+}
+
+func TestSolveSetTo_nonLinearIdentity(t *testing.T) {
+	square := equations.Pow(equations.Add(equations.Var(1, "x", 1), equations.Num(1)), equations.Num(2))
+	eq := equations.NewEquation(square, square)
+	solutions, err := equations.SolveSetTo(&eq, "x")
+	assertSolutionSet(t, solutions, err, "R")
+}
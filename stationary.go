@@ -0,0 +1,540 @@
+package equations
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+)
+
+type PointKind int
+
+const (
+	Minimum PointKind = iota
+	Maximum
+	Saddle
+	Degenerate
+)
+
+func (k PointKind) String() string {
+	switch k {
+	default:
+		panic(fmt.Sprintf("unknown point kind %d", int(k)))
+	case Minimum:
+		return "minimum"
+	case Maximum:
+		return "maximum"
+	case Saddle:
+		return "saddle"
+	case Degenerate:
+		return "degenerate"
+	}
+}
+
+type StationaryPoint struct {
+	Point       map[string]float64
+	Multipliers []float64
+	Value       float64
+	Kind        PointKind
+}
+
+func (p StationaryPoint) String() string {
+	names := make([]string, 0, len(p.Point))
+	for name := range p.Point {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	assignments := make([]string, len(names))
+	for i, name := range names {
+		assignments[i] = fmt.Sprintf("%v = %g", name, p.Point[name])
+	}
+	return fmt.Sprintf("%v %g at (%v)", p.Kind, p.Value, strings.Join(assignments, ", "))
+}
+
+const stationaryTolerance = 1e-9
+
+var errNotIsolated = errors.New("stationary points are not isolated")
+
+var ErrIncomplete = errors.New("stationary points were found numerically and may be incomplete")
+
+func Stationary(expr value, vars []string, constraints ...equation) ([]StationaryPoint, error) {
+	if len(vars) == 0 {
+		return nil, errors.New("no variables given")
+	}
+	if len(constraints) >= len(vars) {
+		return nil, fmt.Errorf("%d constraints leave no freedom in %d variables", len(constraints), len(vars))
+	}
+
+	used := make(map[string]bool)
+	for _, name := range append(Variables(expr), vars...) {
+		used[name] = true
+	}
+	for _, c := range constraints {
+		for _, name := range append(Variables(c.left), Variables(c.right)...) {
+			used[name] = true
+		}
+	}
+
+	lagrangian := expr
+	unknowns := append([]string(nil), vars...)
+	residuals := make([]value, len(constraints))
+	next := 1
+	for i, c := range constraints {
+		for used[fmt.Sprintf("λ%d", next)] {
+			next++
+		}
+		multiplier := fmt.Sprintf("λ%d", next)
+		next++
+		residuals[i] = Sub(c.left, c.right).execute()
+		lagrangian = Sub(lagrangian, Mul(Var(1, multiplier, 1), residuals[i]))
+		unknowns = append(unknowns, multiplier)
+	}
+	for _, name := range Variables(lagrangian) {
+		if !containsName(unknowns, name) {
+			return nil, fmt.Errorf("stationary points depend on the unbound variable %v", name)
+		}
+	}
+
+	gradient := make([]value, len(vars))
+	for i, name := range vars {
+		derivative, err := Derive(lagrangian, name)
+		if err != nil {
+			return nil, err
+		}
+		gradient[i] = derivative
+	}
+	system := append(gradient, residuals...)
+
+	solutions, err := solveGradient(system, unknowns)
+	if err == errNotIsolated {
+		return nil, err
+	}
+	complete := err == nil
+	if !complete {
+		solutions, err = solveGradientNumerically(system, unknowns)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	points := make([]StationaryPoint, 0, len(solutions))
+	for _, solution := range solutions {
+		if !satisfies(system, solution) || containsPoint(points, solution, vars) {
+			continue
+		}
+		point, err := classify(expr, lagrangian, vars, residuals, solution)
+		if err != nil {
+			return nil, err
+		}
+		points = append(points, point)
+	}
+	sort.Slice(points, func(i, j int) bool {
+		for _, name := range vars {
+			if points[i].Point[name] != points[j].Point[name] {
+				return points[i].Point[name] < points[j].Point[name]
+			}
+		}
+		return false
+	})
+	if !complete {
+		return points, ErrIncomplete
+	}
+	return points, nil
+}
+
+func solveGradient(residuals []value, unknowns []string) ([]map[string]float64, error) {
+	remaining := make([]value, 0, len(residuals))
+	for _, r := range residuals {
+		if !isZero(r) {
+			remaining = append(remaining, r)
+		}
+	}
+	if len(unknowns) == 0 {
+		if satisfies(remaining, nil) {
+			return []map[string]float64{{}}, nil
+		}
+		return nil, nil
+	}
+	if len(remaining) == 0 {
+		return nil, errNotIsolated
+	}
+
+	sort.SliceStable(remaining, func(i, j int) bool {
+		return countVariables(remaining[i], unknowns) < countVariables(remaining[j], unknowns)
+	})
+	for i, r := range remaining {
+		for _, name := range unknowns {
+			if !containsVariable(r, name) {
+				continue
+			}
+			candidates, ok := solveResidual(r, name)
+			if !ok {
+				continue
+			}
+
+			others := append(append([]value(nil), remaining[:i]...), remaining[i+1:]...)
+			rest := without(unknowns, name)
+			solutions := make([]map[string]float64, 0)
+			for _, candidate := range candidates {
+				substituted := make([]value, len(others))
+				for k, other := range others {
+					substituted[k] = Substitute(other, map[string]value{name: candidate}).execute()
+				}
+				partial, err := solveGradient(substituted, rest)
+				if err != nil {
+					return nil, err
+				}
+				for _, solution := range partial {
+					x, err := Evaluate(candidate, solution)
+					if err != nil {
+						return nil, err
+					}
+					if !isFinite(x) {
+						continue
+					}
+					solution[name] = x
+					solutions = append(solutions, solution)
+				}
+			}
+			return solutions, nil
+		}
+	}
+	return nil, errors.New("gradient system cannot be solved symbolically")
+}
+
+func solveResidual(r value, name string) ([]value, bool) {
+	eq := NewEquation(r, Num(0))
+	if len(Variables(r)) == 1 {
+		if set, err := SolveComplexTo(&eq, name); err == nil && set.Kind != AllReals {
+			values := make([]value, 0, len(set.Values))
+			for _, v := range set.Values {
+				if math.Abs(v.imaginary) <= complexTolerance {
+					values = append(values, Num(v.number))
+				}
+			}
+			return values, true
+		}
+	}
+
+	if !isolatable(r, name) {
+		return nil, false
+	}
+	set, err := SolveSetTo(&eq, name)
+	if err != nil || (set.Kind != FiniteSet && set.Kind != EmptySet) {
+		return nil, false
+	}
+	for _, v := range set.Values {
+		if containsVariable(v, name) {
+			return nil, false
+		}
+	}
+	return set.Values, true
+}
+
+func isolatable(val value, name string) bool {
+	if !containsVariable(val, name) {
+		return true
+	}
+	if _, unary := functions[val.op]; unary {
+		_, invertible := inverseFunctions[val.op]
+		return invertible && isolatable(*val.left, name)
+	}
+	if val.op == "^" {
+		return false
+	}
+	if val.left == nil {
+		return true
+	}
+	return isolatable(*val.left, name) && isolatable(*val.right, name)
+}
+
+func countVariables(val value, names []string) int {
+	count := 0
+	for _, name := range names {
+		if containsVariable(val, name) {
+			count++
+		}
+	}
+	return count
+}
+
+func containsName(names []string, name string) bool {
+	for _, n := range names {
+		if n == name {
+			return true
+		}
+	}
+	return false
+}
+
+func without(names []string, name string) []string {
+	result := make([]string, 0, len(names))
+	for _, n := range names {
+		if n != name {
+			result = append(result, n)
+		}
+	}
+	return result
+}
+
+func satisfies(residuals []value, solution map[string]float64) bool {
+	for _, r := range residuals {
+		x, err := Evaluate(r, solution)
+		if err != nil || !(math.Abs(x) <= 1e-6) {
+			return false
+		}
+	}
+	return true
+}
+
+func containsPoint(points []StationaryPoint, solution map[string]float64, vars []string) bool {
+	for _, p := range points {
+		same := true
+		for _, name := range vars {
+			if math.Abs(p.Point[name]-solution[name]) > 1e-6*(1+math.Abs(solution[name])) {
+				same = false
+				break
+			}
+		}
+		if same {
+			return true
+		}
+	}
+	return false
+}
+
+func solveGradientNumerically(residuals []value, unknowns []string) ([]map[string]float64, error) {
+	starts := []float64{-5, -1, 0, 0.5, 2, 7}
+	if len(unknowns) > 3 {
+		starts = []float64{-2, 0.5, 3}
+	}
+	eqs := make([]equation, len(residuals))
+	for i, r := range residuals {
+		eqs[i] = NewEquation(r, Num(0))
+	}
+
+	solutions := make([]map[string]float64, 0)
+	index := make([]int, len(unknowns))
+	for {
+		guess := make(map[string]float64, len(unknowns))
+		for i, name := range unknowns {
+			guess[name] = starts[index[i]]
+		}
+		result, err := SolveSystem(guess, SystemOptions{}, eqs...)
+		if err != nil {
+			return nil, err
+		}
+		if result.Converged {
+			solutions = append(solutions, result.Solution)
+		}
+
+		i := 0
+		for ; i < len(index); i++ {
+			index[i]++
+			if index[i] < len(starts) {
+				break
+			}
+			index[i] = 0
+		}
+		if i == len(index) {
+			return solutions, nil
+		}
+	}
+}
+
+func classify(expr, lagrangian value, vars []string, constraints []value, solution map[string]float64) (StationaryPoint, error) {
+	n := len(vars)
+	hessian := make([][]float64, n)
+	for i, a := range vars {
+		hessian[i] = make([]float64, n)
+		da, err := Derive(lagrangian, a)
+		if err != nil {
+			return StationaryPoint{}, err
+		}
+		for j, b := range vars {
+			dab, err := Derive(da, b)
+			if err != nil {
+				return StationaryPoint{}, err
+			}
+			h, err := Evaluate(dab, solution)
+			if err != nil {
+				return StationaryPoint{}, err
+			}
+			hessian[i][j] = h
+		}
+	}
+
+	jacobian := make([][]float64, len(constraints))
+	multipliers := make([]float64, len(constraints))
+	for i, c := range constraints {
+		jacobian[i] = make([]float64, n)
+		for j, name := range vars {
+			dc, err := Derive(c, name)
+			if err != nil {
+				return StationaryPoint{}, err
+			}
+			d, err := Evaluate(dc, solution)
+			if err != nil {
+				return StationaryPoint{}, err
+			}
+			jacobian[i][j] = d
+		}
+		multipliers[i] = solution[fmt.Sprintf("λ%d", i+1)]
+	}
+
+	point := make(map[string]float64, n)
+	for _, name := range vars {
+		point[name] = solution[name]
+	}
+	f, err := Evaluate(expr, point)
+	if err != nil {
+		return StationaryPoint{}, err
+	}
+	return StationaryPoint{point, multipliers, f, curvature(project(hessian, nullSpace(jacobian, n)))}, nil
+}
+
+func nullSpace(a [][]float64, n int) [][]float64 {
+	if len(a) == 0 {
+		basis := make([][]float64, n)
+		for i := range basis {
+			basis[i] = make([]float64, n)
+			basis[i][i] = 1
+		}
+		return basis
+	}
+
+	m := make([][]float64, len(a))
+	for i := range a {
+		m[i] = append([]float64(nil), a[i]...)
+	}
+	pivots := make([]int, 0, len(m))
+	row := 0
+	for column := 0; column < n && row < len(m); column++ {
+		best := row
+		for i := row + 1; i < len(m); i++ {
+			if math.Abs(m[i][column]) > math.Abs(m[best][column]) {
+				best = i
+			}
+		}
+		if math.Abs(m[best][column]) <= stationaryTolerance {
+			continue
+		}
+		m[row], m[best] = m[best], m[row]
+		p := m[row][column]
+		for j := range m[row] {
+			m[row][j] /= p
+		}
+		for i := range m {
+			if i != row {
+				f := m[i][column]
+				for j := range m[i] {
+					m[i][j] -= f * m[row][j]
+				}
+			}
+		}
+		pivots = append(pivots, column)
+		row++
+	}
+
+	isPivot := make(map[int]int)
+	for r, column := range pivots {
+		isPivot[column] = r
+	}
+	basis := make([][]float64, 0, n-len(pivots))
+	for free := 0; free < n; free++ {
+		if _, pivot := isPivot[free]; pivot {
+			continue
+		}
+		v := make([]float64, n)
+		v[free] = 1
+		for column, r := range isPivot {
+			v[column] = -m[r][free]
+		}
+		basis = append(basis, v)
+	}
+	return basis
+}
+
+func project(h, basis [][]float64) [][]float64 {
+	k := len(basis)
+	result := make([][]float64, k)
+	for a := range result {
+		result[a] = make([]float64, k)
+		for b := range result[a] {
+			for i := range h {
+				for j := range h[i] {
+					result[a][b] += basis[a][i] * h[i][j] * basis[b][j]
+				}
+			}
+		}
+	}
+	return result
+}
+
+func curvature(h [][]float64) PointKind {
+	positive, negative := 0, 0
+	for _, eigenvalue := range symmetricEigenvalues(h) {
+		switch {
+		case eigenvalue > stationaryTolerance:
+			positive++
+		case eigenvalue < -stationaryTolerance:
+			negative++
+		}
+	}
+	switch {
+	case positive > 0 && negative > 0:
+		return Saddle
+	case positive == len(h):
+		return Minimum
+	case negative == len(h):
+		return Maximum
+	}
+	return Degenerate
+}
+
+func symmetricEigenvalues(h [][]float64) []float64 {
+	n := len(h)
+	a := make([][]float64, n)
+	for i := range h {
+		a[i] = append([]float64(nil), h[i]...)
+	}
+
+	for sweep := 0; sweep < 50; sweep++ {
+		off := 0.0
+		for i := 0; i < n; i++ {
+			for j := i + 1; j < n; j++ {
+				off += a[i][j] * a[i][j]
+			}
+		}
+		if off <= 1e-24 {
+			break
+		}
+
+		for p := 0; p < n; p++ {
+			for q := p + 1; q < n; q++ {
+				if a[p][q] == 0 {
+					continue
+				}
+				theta := (a[q][q] - a[p][p]) / (2 * a[p][q])
+				t := math.Copysign(1, theta) / (math.Abs(theta) + math.Sqrt(theta*theta+1))
+				c := 1 / math.Sqrt(t*t+1)
+				s := t * c
+				for k := 0; k < n; k++ {
+					akp, akq := a[k][p], a[k][q]
+					a[k][p], a[k][q] = c*akp-s*akq, s*akp+c*akq
+				}
+				for k := 0; k < n; k++ {
+					apk, aqk := a[p][k], a[q][k]
+					a[p][k], a[q][k] = c*apk-s*aqk, s*apk+c*aqk
+				}
+			}
+		}
+	}
+
+	eigenvalues := make([]float64, n)
+	for i := range eigenvalues {
+		eigenvalues[i] = a[i][i]
+	}
+	return eigenvalues
+}
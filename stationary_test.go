@@ -0,0 +1,145 @@
+package equations_test
+
+import (
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/gossie/equations"
+)
+
+func assertStationary(t *testing.T, point equations.StationaryPoint, kind equations.PointKind, value float64, coordinates map[string]float64) {
+	t.Helper()
+	if point.Kind != kind || math.Abs(point.Value-value) > 1e-6 {
+		t.Fatalf("expected %v to be a %v with value %v", point, kind, value)
+	}
+	for name, expected := range coordinates {
+		if math.Abs(point.Point[name]-expected) > 1e-6 {
+			t.Fatalf("expected %v = %v in %v", name, expected, point)
+		}
+	}
+}
+
+func TestStationary_singleVariable(t *testing.T) {
+	points, err := equations.Stationary(equations.Sub(equations.Var(1, "x", 3), equations.Var(3, "x", 1)), []string{"x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != 2 {
+		t.Fatalf("expected two stationary points, got %v", points)
+	}
+	assertStationary(t, points[0], equations.Maximum, 2, map[string]float64{"x": -1})
+	assertStationary(t, points[1], equations.Minimum, -2, map[string]float64{"x": 1})
+
+	if points[1].String() != "minimum -2 at (x = 1)" {
+		t.Fatalf("unexpected string %v", points[1])
+	}
+}
+
+func TestStationary_transcendental(t *testing.T) {
+	x := equations.Var(1, "x", 1)
+	expr := equations.Add(equations.Exp(x), equations.Exp(equations.Mul(equations.Num(-2), x)))
+
+	points, err := equations.Stationary(expr, []string{"x"})
+	if err != nil && !errors.Is(err, equations.ErrIncomplete) {
+		t.Fatal(err)
+	}
+	if len(points) != 1 {
+		t.Fatalf("expected one stationary point, got %v", points)
+	}
+	minimum := math.Ln2 / 3
+	assertStationary(t, points[0], equations.Minimum, math.Exp(minimum)+math.Exp(-2*minimum), map[string]float64{"x": minimum})
+}
+
+func TestStationary_numericFallbackIsIncomplete(t *testing.T) {
+	points, err := equations.Stationary(equations.Sin(equations.Var(1, "x", 1)), []string{"x"})
+	if !errors.Is(err, equations.ErrIncomplete) {
+		t.Fatalf("expected the numeric points %v to be flagged incomplete, got %v", points, err)
+	}
+	if len(points) == 0 {
+		t.Fatal("expected the points found so far")
+	}
+}
+
+func TestStationary_multipleVariables(t *testing.T) {
+	expr := equations.Add(equations.Sub(equations.Var(1, "x", 3), equations.Var(3, "x", 1)), equations.Var(1, "y", 2))
+
+	points, err := equations.Stationary(expr, []string{"x", "y"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != 2 {
+		t.Fatalf("expected two stationary points, got %v", points)
+	}
+	assertStationary(t, points[0], equations.Saddle, 2, map[string]float64{"x": -1, "y": 0})
+	assertStationary(t, points[1], equations.Minimum, -2, map[string]float64{"x": 1, "y": 0})
+}
+
+func TestStationary_degenerate(t *testing.T) {
+	points, err := equations.Stationary(equations.Var(1, "x", 4), []string{"x"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertStationary(t, points[0], equations.Degenerate, 0, map[string]float64{"x": 0})
+
+	sum := equations.Add(equations.Var(1, "x", 1), equations.Var(1, "y", 1))
+	if _, err := equations.Stationary(equations.Pow(sum, equations.Num(2)), []string{"x", "y"}); err == nil {
+		t.Fatal("expected an error for a line of minima")
+	}
+}
+
+func TestStationary_lagrangeMultipliers(t *testing.T) {
+	x, y := equations.Var(1, "x", 1), equations.Var(1, "y", 1)
+
+	points, err := equations.Stationary(equations.Mul(x, y), []string{"x", "y"}, equations.NewEquation(equations.Add(x, y), equations.Num(10)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(points) != 1 {
+		t.Fatalf("expected one stationary point, got %v", points)
+	}
+	assertStationary(t, points[0], equations.Maximum, 25, map[string]float64{"x": 5, "y": 5})
+	if math.Abs(points[0].Multipliers[0]-5) > 1e-9 {
+		t.Fatalf("expected multiplier 5, got %v", points[0].Multipliers)
+	}
+
+	circle := equations.NewEquation(equations.Add(equations.Var(1, "x", 2), equations.Var(1, "y", 2)), equations.Num(2))
+	points, err = equations.Stationary(equations.Add(x, y), []string{"x", "y"}, circle)
+	if err != nil && !errors.Is(err, equations.ErrIncomplete) {
+		t.Fatal(err)
+	}
+	if len(points) != 2 {
+		t.Fatalf("expected two stationary points, got %v", points)
+	}
+	assertStationary(t, points[0], equations.Minimum, -2, map[string]float64{"x": -1, "y": -1})
+	assertStationary(t, points[1], equations.Maximum, 2, map[string]float64{"x": 1, "y": 1})
+}
+
+func TestStationary_tooManyConstraints(t *testing.T) {
+	x := equations.Var(1, "x", 1)
+	if _, err := equations.Stationary(x, []string{"x"}, equations.NewEquation(x, equations.Num(1))); err == nil {
+		t.Fatal("expected an error for an overdetermined problem")
+	}
+}
+
+func TestStationary_unboundVariable(t *testing.T) {
+	expr := equations.Sub(equations.Mul(equations.Var(1, "a", 1), equations.Var(1, "x", 2)), equations.Var(1, "x", 1))
+	if points, err := equations.Stationary(expr, []string{"x"}); err == nil {
+		t.Fatalf("expected an error for the unbound variable a, got %v", points)
+	}
+}
+
+func TestStationary_multiplierNamesDoNotCollide(t *testing.T) {
+	x, l := equations.Var(1, "x", 1), equations.Var(1, "λ1", 1)
+	circle := equations.NewEquation(equations.Add(equations.Var(1, "x", 2), equations.Var(1, "λ1", 2)), equations.Num(2))
+
+	points, err := equations.Stationary(equations.Add(x, l), []string{"x", "λ1"}, circle)
+	if err != nil && !errors.Is(err, equations.ErrIncomplete) {
+		t.Fatal(err)
+	}
+	if len(points) != 2 {
+		t.Fatalf("expected two stationary points, got %v", points)
+	}
+	assertStationary(t, points[0], equations.Minimum, -2, map[string]float64{"x": -1, "λ1": -1})
+	assertStationary(t, points[1], equations.Maximum, 2, map[string]float64{"x": 1, "λ1": 1})
+}
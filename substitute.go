@@ -0,0 +1,57 @@
+package equations
+
+func replaceTerm(current, val value) value {
+	if current.exponent != 1 {
+		val = Pow(val, Num(current.exponent))
+	}
+	return Mul(coefficient(current), val)
+}
+
+type Replacements map[string]value
+
+func Substitute(expr value, replacements Replacements) value {
+	if expr.op == "var" {
+		if val, present := replacements[expr.name]; present {
+			return replaceTerm(expr, val)
+		}
+		return expr
+	}
+
+	expr.held = false
+	if expr.left != nil {
+		l := Substitute(*expr.left, replacements)
+		expr.left = &l
+	}
+	if expr.right != nil {
+		r := Substitute(*expr.right, replacements)
+		expr.right = &r
+	}
+	return expr
+}
+
+func SubstituteEquation(eq *equation, replacements Replacements) equation {
+	return NewEquation(Substitute(eq.left, replacements), Substitute(eq.right, replacements))
+}
+
+func Rename(expr value, names map[string]string) value {
+	if expr.op == "var" {
+		if name, present := names[expr.name]; present {
+			expr.name = name
+		}
+		return expr
+	}
+
+	if expr.left != nil {
+		l := Rename(*expr.left, names)
+		expr.left = &l
+	}
+	if expr.right != nil {
+		r := Rename(*expr.right, names)
+		expr.right = &r
+	}
+	return expr
+}
+
+func RenameEquation(eq *equation, names map[string]string) equation {
+	return NewEquation(Rename(eq.left, names), Rename(eq.right, names))
+}
@@ -0,0 +1,54 @@
+package equations_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gossie/equations"
+)
+
+func TestSubstitute_respectsExponents(t *testing.T) {
+	result := equations.Substitute(equations.Var(3, "x", 2), equations.Replacements{"x": equations.Add(equations.Var(1, "y", 1), equations.Num(1))})
+
+	if result.String() != "(3.000000 * ((1.000000y + 1.000000) ^ 2.000000))" {
+		t.Fatalf("expected %v to be (3.000000 * ((1.000000y + 1.000000) ^ 2.000000))", result)
+	}
+	if y, _ := equations.Evaluate(result, map[string]float64{"y": 2}); y != 27 {
+		t.Fatalf("expected %v to be 27 for y = 2", y)
+	}
+}
+
+func TestSubstitute_simultaneous(t *testing.T) {
+	swapped := equations.Substitute(equations.Sub(equations.Var(1, "x", 1), equations.Var(2, "y", 1)), equations.Replacements{"x": equations.Var(1, "y", 1), "y": equations.Var(1, "x", 1)})
+
+	if swapped.String() != "((1.000000 * 1.000000y) - (2.000000 * 1.000000x))" {
+		t.Fatalf("expected %v to be ((1.000000 * 1.000000y) - (2.000000 * 1.000000x))", swapped)
+	}
+}
+
+func TestSubstitute_functions(t *testing.T) {
+	result := equations.Substitute(equations.Add(equations.Sin(equations.Var(1, "x", 1)), equations.Pow(equations.Var(1, "x", 1), equations.Var(1, "n", 1))), equations.Replacements{"x": equations.Num(2), "n": equations.Num(3)})
+
+	simplified := equations.NewSimplifier().Simplify(result)
+	if simplified.String() != equations.Num(math.Sin(2)+8).String() {
+		t.Fatalf("expected %v to be %v", simplified, math.Sin(2)+8)
+	}
+}
+
+func TestSubstituteEquation(t *testing.T) {
+	eq := equations.NewEquation(equations.Var(1, "a", 2), equations.Add(equations.Var(1, "b", 2), equations.Var(1, "c", 2)))
+
+	result := equations.SubstituteEquation(&eq, equations.Replacements{"a": equations.Num(5), "b": equations.Num(3), "c": equations.Num(4)})
+	if !result.IsTrue() {
+		t.Fatalf("expected %v to be true", result)
+	}
+}
+
+func TestRename(t *testing.T) {
+	eq := equations.NewEquation(equations.Mul(equations.Var(2, "x", 3), equations.Var(1, "y", 1)), equations.Exp(equations.Var(4, "x", 1)))
+
+	result := equations.RenameEquation(&eq, map[string]string{"x": "u", "y": "x"})
+	if result.String() != "(2.000000u^3 * 1.000000x) = exp(4.000000u)" {
+		t.Fatalf("expected %v to be (2.000000u^3 * 1.000000x) = exp(4.000000u)", result)
+	}
+}
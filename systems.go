@@ -0,0 +1,361 @@
+package equations
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+)
+
+type SystemMethod int
+
+const (
+	DampedNewton SystemMethod = iota
+	LevenbergMarquardt
+)
+
+type SystemOptions struct {
+	Method        SystemMethod
+	Tolerance     float64
+	MaxIterations int
+	Damping       float64
+}
+
+type SystemResult struct {
+	Solution      map[string]float64
+	Iterations    int
+	ResidualNorms []float64
+	Converged     bool
+	Stationary    bool
+	Reason        string
+}
+
+func (sr *SystemResult) Residual() float64 {
+	return sr.ResidualNorms[len(sr.ResidualNorms)-1]
+}
+
+func (opts SystemOptions) withDefaults() SystemOptions {
+	if opts.Tolerance <= 0 {
+		opts.Tolerance = 1e-10
+	}
+	if opts.MaxIterations <= 0 {
+		opts.MaxIterations = 100
+	}
+	if opts.Damping <= 0 {
+		opts.Damping = 1e-3
+	}
+	return opts
+}
+
+type system struct {
+	vars      []string
+	residuals []value
+	jacobian  [][]value
+}
+
+func newSystem(eqs []equation, vars []string) (system, error) {
+	s := system{vars: vars, residuals: make([]value, len(eqs)), jacobian: make([][]value, len(eqs))}
+	for i, eq := range eqs {
+		s.residuals[i] = Sub(eq.left, eq.right).execute()
+		s.jacobian[i] = make([]value, len(vars))
+		for j, name := range vars {
+			derivative, err := Derive(s.residuals[i], name)
+			if err != nil {
+				return system{}, err
+			}
+			s.jacobian[i][j] = derivative
+		}
+	}
+	return s, nil
+}
+
+func (s system) bind(x []float64) map[string]float64 {
+	vars := make(map[string]float64, len(s.vars))
+	for i, name := range s.vars {
+		vars[name] = x[i]
+	}
+	return vars
+}
+
+func (s system) residual(x []float64) ([]float64, error) {
+	vars := s.bind(x)
+	r := make([]float64, len(s.residuals))
+	for i, residual := range s.residuals {
+		ri, err := Evaluate(residual, vars)
+		if err != nil {
+			return nil, err
+		}
+		r[i] = ri
+	}
+	return r, nil
+}
+
+func (s system) evaluateJacobian(x []float64) ([][]float64, error) {
+	vars := s.bind(x)
+	j := make([][]float64, len(s.jacobian))
+	for row := range s.jacobian {
+		j[row] = make([]float64, len(s.vars))
+		for col, derivative := range s.jacobian[row] {
+			d, err := Evaluate(derivative, vars)
+			if err != nil {
+				return nil, err
+			}
+			j[row][col] = d
+		}
+	}
+	return j, nil
+}
+
+func SolveSystem(guess map[string]float64, opts SystemOptions, eqs ...equation) (*SystemResult, error) {
+	opts = opts.withDefaults()
+	if len(eqs) == 0 {
+		return nil, errors.New("no equations given")
+	}
+
+	vars := make([]string, 0, len(guess))
+	for name := range guess {
+		vars = append(vars, name)
+	}
+	sort.Strings(vars)
+	x := make([]float64, len(vars))
+	for i, name := range vars {
+		x[i] = guess[name]
+	}
+
+	s, err := newSystem(eqs, vars)
+	if err != nil {
+		return nil, err
+	}
+	switch opts.Method {
+	default:
+		return nil, fmt.Errorf("unknown system method %d", opts.Method)
+	case DampedNewton:
+		if len(eqs) != len(vars) {
+			return nil, fmt.Errorf("damped Newton needs as many equations as variables, got %d and %d", len(eqs), len(vars))
+		}
+		return s.dampedNewton(x, opts)
+	case LevenbergMarquardt:
+		return s.levenbergMarquardt(x, opts)
+	}
+}
+
+func (s system) dampedNewton(x []float64, opts SystemOptions) (*SystemResult, error) {
+	r, err := s.residual(x)
+	if err != nil {
+		return nil, err
+	}
+	result := &SystemResult{ResidualNorms: []float64{norm(r)}}
+
+	for result.Iterations < opts.MaxIterations {
+		if result.Residual() <= opts.Tolerance {
+			return result.converged(s.bind(x)), nil
+		}
+		if !isFinite(result.Residual()) {
+			return result.failed(s.bind(x), "residual is not finite"), nil
+		}
+		result.Iterations++
+
+		j, err := s.evaluateJacobian(x)
+		if err != nil {
+			return nil, err
+		}
+		dx, err := solveLinear(j, scale(r, -1))
+		if err != nil {
+			return result.failed(s.bind(x), "jacobian is singular"), nil
+		}
+
+		t := 1.0
+		for {
+			candidate := addScaled(x, dx, t)
+			rc, err := s.residual(candidate)
+			if err != nil {
+				return nil, err
+			}
+			if isFinite(norm(rc)) && norm(rc) <= (1-1e-4*t)*result.Residual() {
+				x, r = candidate, rc
+				break
+			}
+			t /= 2
+			if t < 1e-10 {
+				return result.failed(s.bind(x), "line search could not reduce the residual"), nil
+			}
+		}
+		result.ResidualNorms = append(result.ResidualNorms, norm(r))
+
+		if norm(dx)*t <= opts.Tolerance*(1+norm(x)) && result.Residual() <= opts.Tolerance {
+			return result.converged(s.bind(x)), nil
+		}
+	}
+
+	if result.Residual() <= opts.Tolerance {
+		return result.converged(s.bind(x)), nil
+	}
+	return result.failed(s.bind(x), "iteration limit reached"), nil
+}
+
+func (s system) levenbergMarquardt(x []float64, opts SystemOptions) (*SystemResult, error) {
+	r, err := s.residual(x)
+	if err != nil {
+		return nil, err
+	}
+	result := &SystemResult{ResidualNorms: []float64{norm(r)}}
+	lambda := opts.Damping
+
+	for result.Iterations < opts.MaxIterations {
+		if result.Residual() <= opts.Tolerance {
+			return result.converged(s.bind(x)), nil
+		}
+		if !isFinite(result.Residual()) {
+			return result.failed(s.bind(x), "residual is not finite"), nil
+		}
+		result.Iterations++
+
+		j, err := s.evaluateJacobian(x)
+		if err != nil {
+			return nil, err
+		}
+		jtj, jtr := normalEquations(j, r)
+		if norm(jtr) <= opts.Tolerance*(1+result.Residual()) {
+			return result.stationary(s.bind(x)), nil
+		}
+
+		for {
+			a := make([][]float64, len(jtj))
+			for i := range jtj {
+				a[i] = append([]float64(nil), jtj[i]...)
+				a[i][i] += lambda * math.Max(jtj[i][i], 1e-12)
+			}
+			dx, err := solveLinear(a, scale(jtr, -1))
+			if err != nil {
+				return result.failed(s.bind(x), "damped normal equations are singular"), nil
+			}
+
+			candidate := addScaled(x, dx, 1)
+			rc, err := s.residual(candidate)
+			if err != nil {
+				return nil, err
+			}
+			if isFinite(norm(rc)) && norm(rc) < result.Residual() {
+				x, r = candidate, rc
+				lambda = math.Max(lambda/10, 1e-15)
+				break
+			}
+			lambda *= 10
+			if lambda > 1e15 {
+				if norm(jtr) <= math.Sqrt(opts.Tolerance)*(1+result.Residual()) {
+					return result.stationary(s.bind(x)), nil
+				}
+				return result.failed(s.bind(x), "damping grew without reducing the residual"), nil
+			}
+		}
+		result.ResidualNorms = append(result.ResidualNorms, norm(r))
+	}
+
+	if result.Residual() <= opts.Tolerance {
+		return result.converged(s.bind(x)), nil
+	}
+	return result.failed(s.bind(x), "iteration limit reached"), nil
+}
+
+func (sr *SystemResult) converged(solution map[string]float64) *SystemResult {
+	sr.Solution = solution
+	sr.Converged = true
+	return sr
+}
+
+func (sr *SystemResult) stationary(solution map[string]float64) *SystemResult {
+	sr.Solution = solution
+	sr.Stationary = true
+	sr.Reason = "gradient vanishes at a least-squares minimum with nonzero residual"
+	return sr
+}
+
+func (sr *SystemResult) failed(solution map[string]float64, reason string) *SystemResult {
+	sr.Solution = solution
+	sr.Reason = reason
+	return sr
+}
+
+func normalEquations(j [][]float64, r []float64) ([][]float64, []float64) {
+	n := len(j[0])
+	jtj := make([][]float64, n)
+	jtr := make([]float64, n)
+	for a := 0; a < n; a++ {
+		jtj[a] = make([]float64, n)
+		for b := 0; b < n; b++ {
+			for i := range j {
+				jtj[a][b] += j[i][a] * j[i][b]
+			}
+		}
+		for i := range j {
+			jtr[a] += j[i][a] * r[i]
+		}
+	}
+	return jtj, jtr
+}
+
+func solveLinear(a [][]float64, b []float64) ([]float64, error) {
+	n := len(b)
+	m := make([][]float64, n)
+	for i := range a {
+		m[i] = append(append([]float64(nil), a[i]...), b[i])
+	}
+
+	for col := 0; col < n; col++ {
+		pivot := col
+		for row := col + 1; row < n; row++ {
+			if math.Abs(m[row][col]) > math.Abs(m[pivot][col]) {
+				pivot = row
+			}
+		}
+		if math.Abs(m[pivot][col]) < 1e-14 {
+			return nil, errors.New("matrix is singular")
+		}
+		m[col], m[pivot] = m[pivot], m[col]
+
+		for row := col + 1; row < n; row++ {
+			factor := m[row][col] / m[col][col]
+			for k := col; k <= n; k++ {
+				m[row][k] -= factor * m[col][k]
+			}
+		}
+	}
+
+	x := make([]float64, n)
+	for row := n - 1; row >= 0; row-- {
+		sum := m[row][n]
+		for k := row + 1; k < n; k++ {
+			sum -= m[row][k] * x[k]
+		}
+		x[row] = sum / m[row][row]
+	}
+	return x, nil
+}
+
+func norm(v []float64) float64 {
+	sum := 0.0
+	for _, x := range v {
+		sum += x * x
+	}
+	return math.Sqrt(sum)
+}
+
+func scale(v []float64, factor float64) []float64 {
+	result := make([]float64, len(v))
+	for i, x := range v {
+		result[i] = factor * x
+	}
+	return result
+}
+
+func addScaled(x, dx []float64, t float64) []float64 {
+	result := make([]float64, len(x))
+	for i := range x {
+		result[i] = x[i] + t*dx[i]
+	}
+	return result
+}
+
+func isFinite(x float64) bool {
+	return !math.IsNaN(x) && !math.IsInf(x, 0)
+}
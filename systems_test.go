@@ -0,0 +1,87 @@
+package equations_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gossie/equations"
+)
+
+func TestSolveSystem_dampedNewton(t *testing.T) {
+	eq1 := equations.NewEquation(equations.Add(equations.Var(1, "x", 2), equations.Var(1, "y", 2)), equations.Num(4))
+	eq2 := equations.NewEquation(equations.Mul(equations.Var(1, "x", 1), equations.Var(1, "y", 1)), equations.Num(1))
+
+	result, err := equations.SolveSystem(map[string]float64{"x": 2, "y": 0.5}, equations.SystemOptions{}, eq1, eq2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	x, y := result.Solution["x"], result.Solution["y"]
+	if !result.Converged || math.Abs(x*x+y*y-4) > 1e-9 || math.Abs(x*y-1) > 1e-9 {
+		t.Fatalf("expected %v to solve the system", result.Solution)
+	}
+}
+
+func TestSolveSystem_threeEquations(t *testing.T) {
+	eq1 := equations.NewEquation(equations.Add(equations.Var(1, "x", 1), equations.Add(equations.Var(1, "y", 1), equations.Var(1, "z", 1))), equations.Num(6))
+	eq2 := equations.NewEquation(equations.Mul(equations.Var(1, "x", 1), equations.Var(1, "y", 1)), equations.Num(2))
+	eq3 := equations.NewEquation(equations.Exp(equations.Sub(equations.Var(1, "z", 1), equations.Num(3))), equations.Num(1))
+
+	for _, method := range []equations.SystemMethod{equations.DampedNewton, equations.LevenbergMarquardt} {
+		result, err := equations.SolveSystem(map[string]float64{"x": 0.8, "y": 2.5, "z": 2}, equations.SystemOptions{Method: method, MaxIterations: 200}, eq1, eq2, eq3)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !result.Converged || math.Abs(result.Solution["x"]-1) > 1e-6 || math.Abs(result.Solution["y"]-2) > 1e-6 || math.Abs(result.Solution["z"]-3) > 1e-6 {
+			t.Fatalf("expected %v to be x=1, y=2, z=3 (%v)", result.Solution, result.Reason)
+		}
+		if result.Residual() > result.ResidualNorms[0] {
+			t.Fatalf("expected residual norms %v to decrease", result.ResidualNorms)
+		}
+	}
+}
+
+func TestSolveSystem_singularJacobian(t *testing.T) {
+	eq1 := equations.NewEquation(equations.Add(equations.Var(1, "x", 2), equations.Num(1)), equations.Num(0))
+
+	result, err := equations.SolveSystem(map[string]float64{"x": 0}, equations.SystemOptions{}, eq1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Converged || result.Reason != "jacobian is singular" {
+		t.Fatalf("expected %v to fail with a singular jacobian", result)
+	}
+}
+
+func TestSolveSystem_missingGuess(t *testing.T) {
+	eq1 := equations.NewEquation(equations.Var(1, "x", 1), equations.Var(1, "y", 1))
+
+	_, err := equations.SolveSystem(map[string]float64{"x": 0}, equations.SystemOptions{}, eq1)
+	if err == nil {
+		t.Fatal("expected an error")
+	}
+}
+
+func TestSolveSystem_dampedNewtonMeetsTolerance(t *testing.T) {
+	eq1 := equations.NewEquation(equations.Exp(equations.Var(1, "x", 1)), equations.Num(3))
+
+	result, err := equations.SolveSystem(map[string]float64{"x": 5}, equations.SystemOptions{}, eq1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !result.Converged || result.Residual() > 1e-10 {
+		t.Fatalf("expected %v to converge below the tolerance", result.ResidualNorms)
+	}
+}
+
+func TestSolveSystem_leastSquaresOptimum(t *testing.T) {
+	eq1 := equations.NewEquation(equations.Var(1, "x", 1), equations.Num(1))
+	eq2 := equations.NewEquation(equations.Var(1, "x", 1), equations.Num(3))
+
+	result, err := equations.SolveSystem(map[string]float64{"x": 0}, equations.SystemOptions{Method: equations.LevenbergMarquardt}, eq1, eq2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.Converged || !result.Stationary || math.Abs(result.Solution["x"]-2) > 1e-9 {
+		t.Fatalf("expected a least-squares minimum at x = 2, got %v (%v)", result.Solution, result.Reason)
+	}
+}
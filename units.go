@@ -0,0 +1,384 @@
+package equations
+
+import (
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+type Dimension [7]int
+
+var baseUnits = [7]string{"m", "kg", "s", "A", "K", "mol", "cd"}
+
+var (
+	Dimensionless = Dimension{}
+	Length        = Dimension{1, 0, 0, 0, 0, 0, 0}
+	Mass          = Dimension{0, 1, 0, 0, 0, 0, 0}
+	Time          = Dimension{0, 0, 1, 0, 0, 0, 0}
+	Current       = Dimension{0, 0, 0, 1, 0, 0, 0}
+	Temperature   = Dimension{0, 0, 0, 0, 1, 0, 0}
+	Amount        = Dimension{0, 0, 0, 0, 0, 1, 0}
+	Luminosity    = Dimension{0, 0, 0, 0, 0, 0, 1}
+)
+
+type unit struct {
+	factor    float64
+	dimension Dimension
+}
+
+var units = map[string]unit{
+	"m":   {1, Length},
+	"kg":  {1, Mass},
+	"s":   {1, Time},
+	"A":   {1, Current},
+	"K":   {1, Temperature},
+	"mol": {1, Amount},
+	"cd":  {1, Luminosity},
+	"km":  {1000, Length},
+	"cm":  {0.01, Length},
+	"mm":  {0.001, Length},
+	"in":  {0.0254, Length},
+	"ft":  {0.3048, Length},
+	"mi":  {1609.344, Length},
+	"g":   {0.001, Mass},
+	"t":   {1000, Mass},
+	"lb":  {0.45359237, Mass},
+	"ms":  {0.001, Time},
+	"min": {60, Time},
+	"h":   {3600, Time},
+	"Hz":  {1, Dimension{0, 0, -1, 0, 0, 0, 0}},
+	"N":   {1, Dimension{1, 1, -2, 0, 0, 0, 0}},
+	"kN":  {1000, Dimension{1, 1, -2, 0, 0, 0, 0}},
+	"Pa":  {1, Dimension{-1, 1, -2, 0, 0, 0, 0}},
+	"bar": {100000, Dimension{-1, 1, -2, 0, 0, 0, 0}},
+	"J":   {1, Dimension{2, 1, -2, 0, 0, 0, 0}},
+	"kJ":  {1000, Dimension{2, 1, -2, 0, 0, 0, 0}},
+	"W":   {1, Dimension{2, 1, -3, 0, 0, 0, 0}},
+	"kW":  {1000, Dimension{2, 1, -3, 0, 0, 0, 0}},
+	"C":   {1, Dimension{0, 0, 1, 1, 0, 0, 0}},
+	"V":   {1, Dimension{2, 1, -3, -1, 0, 0, 0}},
+	"ohm": {1, Dimension{2, 1, -3, -2, 0, 0, 0}},
+}
+
+func (d Dimension) String() string {
+	parts := make([]string, 0, len(d))
+	for i, e := range d {
+		switch e {
+		case 0:
+		case 1:
+			parts = append(parts, baseUnits[i])
+		default:
+			parts = append(parts, fmt.Sprintf("%v^%d", baseUnits[i], e))
+		}
+	}
+	if len(parts) == 0 {
+		return "1"
+	}
+	return strings.Join(parts, " ")
+}
+
+func (d Dimension) times(other Dimension) Dimension {
+	for i := range d {
+		d[i] += other[i]
+	}
+	return d
+}
+
+func (d Dimension) scale(exponent float64) (Dimension, error) {
+	for i := range d {
+		e := float64(d[i]) * exponent
+		if e != math.Trunc(e) {
+			return Dimension{}, fmt.Errorf("%v ^ %v has a fractional dimension", d, exponent)
+		}
+		d[i] = int(e)
+	}
+	return d, nil
+}
+
+func unitName(base string) string {
+	return "[" + base + "]"
+}
+
+func isUnit(name string) bool {
+	_, unit := baseUnit(name)
+	return unit
+}
+
+func inBaseUnits(vars map[string]float64) map[string]float64 {
+	result := make(map[string]float64, len(vars)+len(baseUnits))
+	for name, x := range vars {
+		result[name] = x
+	}
+	for _, base := range baseUnits {
+		result[unitName(base)] = 1
+	}
+	return result
+}
+
+func baseUnit(name string) (int, bool) {
+	if !strings.HasPrefix(name, "[") || !strings.HasSuffix(name, "]") {
+		return 0, false
+	}
+	for i, base := range baseUnits {
+		if unitName(base) == name {
+			return i, true
+		}
+	}
+	return 0, false
+}
+
+func parseUnit(symbol string) (unit, error) {
+	result := unit{1, Dimensionless}
+	divide := false
+	for _, token := range strings.FieldsFunc(strings.ReplaceAll(symbol, "/", " / "), func(r rune) bool { return r == ' ' || r == '*' }) {
+		if token == "/" {
+			divide = true
+			continue
+		}
+
+		name, exponent := token, 1
+		if i := strings.Index(token, "^"); i >= 0 {
+			e, err := strconv.Atoi(token[i+1:])
+			if err != nil {
+				return unit{}, fmt.Errorf("invalid exponent in unit %v", symbol)
+			}
+			name, exponent = token[:i], e
+		}
+		u, known := units[name]
+		if !known {
+			return unit{}, errors.New("unknown unit " + name)
+		}
+		if divide {
+			exponent = -exponent
+			divide = false
+		}
+		dimension, _ := u.dimension.scale(float64(exponent))
+		result = unit{result.factor * math.Pow(u.factor, float64(exponent)), result.dimension.times(dimension)}
+	}
+	return result, nil
+}
+
+func Quantity(magnitude float64, symbol string) (value, error) {
+	u, err := parseUnit(symbol)
+	if err != nil {
+		return value{}, err
+	}
+
+	var result *value
+	for i, e := range u.dimension {
+		if e == 0 {
+			continue
+		}
+		factor := 1.0
+		if result == nil {
+			factor = magnitude * u.factor
+		}
+		current := Var(factor, unitName(baseUnits[i]), float64(e))
+		if result != nil {
+			current = Mul(*result, current)
+		}
+		result = &current
+	}
+	if result == nil {
+		return Num(magnitude * u.factor), nil
+	}
+	return *result, nil
+}
+
+type unitProduct struct {
+	coefficient  float64
+	dimension    Dimension
+	numerators   []value
+	denominators []value
+	units        bool
+}
+
+func simplifyUnits(val value) value {
+	if val.op == "+" || val.op == "-" {
+		l, r := simplifyUnits(*val.left), simplifyUnits(*val.right)
+		val.left, val.right = &l, &r
+		return val
+	}
+
+	p := &unitProduct{coefficient: 1}
+	if !p.collect(val, 1) || !p.units {
+		return val
+	}
+
+	factors := p.numerators
+	if p.coefficient != 1 || len(factors) == 0 {
+		factors = append([]value{Num(p.coefficient)}, factors...)
+	}
+	result := factors[0]
+	for _, n := range factors[1:] {
+		result = Mul(result, n)
+	}
+	for _, d := range p.denominators {
+		result = Div(result, d)
+	}
+
+	scaled := result.op == "num"
+	for i, e := range p.dimension {
+		if e == 0 {
+			continue
+		}
+		if scaled {
+			result, scaled = Var(result.number, unitName(baseUnits[i]), float64(e)), false
+		} else {
+			result = Mul(result, Var(1, unitName(baseUnits[i]), float64(e)))
+		}
+	}
+	return result
+}
+
+func (p *unitProduct) collect(val value, exponent float64) bool {
+	switch val.op {
+	case "num":
+		if val.imaginary != 0 {
+			return false
+		}
+		p.coefficient *= math.Pow(val.number, exponent)
+		return true
+	case "var":
+		i, unit := baseUnit(val.name)
+		if !unit {
+			break
+		}
+		e := val.exponent * exponent
+		if e != math.Trunc(e) {
+			return false
+		}
+		p.coefficient *= math.Pow(val.number, exponent)
+		p.dimension[i] += int(e)
+		p.units = true
+		return true
+	case "*":
+		return p.collect(*val.left, exponent) && p.collect(*val.right, exponent)
+	case "/":
+		return p.collect(*val.left, exponent) && p.collect(*val.right, -exponent)
+	}
+
+	if containsUnit(val) {
+		return false
+	}
+	if exponent > 0 {
+		p.numerators = append(p.numerators, val)
+	} else {
+		p.denominators = append(p.denominators, val)
+	}
+	return true
+}
+
+func containsUnit(val value) bool {
+	if val.op == "var" && isUnit(val.name) {
+		return true
+	}
+	return (val.left != nil && containsUnit(*val.left)) || (val.right != nil && containsUnit(*val.right))
+}
+
+func DimensionOf(val value, vars map[string]Dimension) (Dimension, error) {
+	if _, unary := functions[val.op]; unary {
+		arg, err := DimensionOf(*val.left, vars)
+		if err != nil {
+			return Dimension{}, err
+		}
+		if arg != Dimensionless {
+			return Dimension{}, fmt.Errorf("%v needs a dimensionless argument, got %v", val.op, arg)
+		}
+		return Dimensionless, nil
+	}
+
+	switch val.op {
+	default:
+		return Dimension{}, errors.New("cannot determine dimension of operator " + val.op)
+	case "num", "const":
+		return Dimensionless, nil
+	case "var":
+		if i, isUnit := baseUnit(val.name); isUnit {
+			var d Dimension
+			d[i] = 1
+			return d.scale(val.exponent)
+		}
+		return vars[val.name].scale(val.exponent)
+	case "+", "-", "*", "/":
+		l, err := DimensionOf(*val.left, vars)
+		if err != nil {
+			return Dimension{}, err
+		}
+		r, err := DimensionOf(*val.right, vars)
+		if err != nil {
+			return Dimension{}, err
+		}
+		switch val.op {
+		case "*":
+			return l.times(r), nil
+		case "/":
+			inverse, _ := r.scale(-1)
+			return l.times(inverse), nil
+		default:
+			if isZero(*val.left) {
+				return r, nil
+			}
+			if isZero(*val.right) {
+				return l, nil
+			}
+			if l != r {
+				return Dimension{}, fmt.Errorf("cannot combine %v and %v in %v", l, r, val)
+			}
+			return l, nil
+		}
+	case "^":
+		base, err := DimensionOf(*val.left, vars)
+		if err != nil {
+			return Dimension{}, err
+		}
+		exponent := val.right.execute()
+		if d, err := DimensionOf(exponent, vars); err != nil || d != Dimensionless {
+			return Dimension{}, fmt.Errorf("exponent of %v must be dimensionless", val)
+		}
+		if base == Dimensionless {
+			return Dimensionless, nil
+		}
+		if exponent.op != "num" {
+			return Dimension{}, fmt.Errorf("exponent of %v must be a number", val)
+		}
+		return base.scale(exponent.number)
+	}
+}
+
+func CheckDimensions(eq *equation, vars map[string]Dimension) error {
+	l, err := DimensionOf(eq.left, vars)
+	if err != nil {
+		return err
+	}
+	r, err := DimensionOf(eq.right, vars)
+	if err != nil {
+		return err
+	}
+	if l != r {
+		return fmt.Errorf("sides of %v disagree: %v != %v", eq, l, r)
+	}
+	return nil
+}
+
+func Convert(val value, symbol string) (float64, error) {
+	u, err := parseUnit(symbol)
+	if err != nil {
+		return 0, err
+	}
+	d, err := DimensionOf(val, nil)
+	if err != nil {
+		return 0, err
+	}
+	if d != u.dimension {
+		return 0, fmt.Errorf("cannot convert %v to %v", d, symbol)
+	}
+
+	magnitude, err := Evaluate(val, inBaseUnits(nil))
+	if err != nil {
+		return 0, err
+	}
+	return magnitude / u.factor, nil
+}
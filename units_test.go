@@ -0,0 +1,164 @@
+package equations_test
+
+import (
+	"math"
+	"testing"
+
+	"github.com/gossie/equations"
+)
+
+func TestQuantity_conversion(t *testing.T) {
+	km, _ := equations.Quantity(1, "km")
+	m, _ := equations.Quantity(500, "m")
+	expected, _ := equations.Quantity(1.5, "km")
+
+	eq := equations.NewEquation(equations.Add(km, m), expected)
+	if !eq.IsTrue() {
+		t.Fatalf("expected %v to be true", eq)
+	}
+
+	result, err := equations.Convert(equations.Add(km, m), "mi")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(result-1500/1609.344) > 1e-12 {
+		t.Fatalf("expected %v to be %v", result, 1500/1609.344)
+	}
+}
+
+func TestQuantity_derivedUnits(t *testing.T) {
+	mass, _ := equations.Quantity(2, "kg")
+	acceleration, _ := equations.Quantity(3, "m/s^2")
+	force := equations.Mul(mass, acceleration)
+
+	dimension, err := equations.DimensionOf(force, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if dimension.String() != "m kg s^-2" {
+		t.Fatalf("expected %v to be m kg s^-2", dimension)
+	}
+
+	result, err := equations.Convert(force, "kN")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(result-0.006) > 1e-15 {
+		t.Fatalf("expected %v to be 0.006", result)
+	}
+
+	if _, err := equations.Convert(force, "J"); err == nil {
+		t.Fatal("expected newtons not to convert to joules")
+	}
+}
+
+func TestDimensionOf_mismatch(t *testing.T) {
+	length, _ := equations.Quantity(1, "m")
+	duration, _ := equations.Quantity(1, "s")
+
+	if _, err := equations.DimensionOf(equations.Sub(length, duration), nil); err == nil {
+		t.Fatal("expected metres minus seconds to fail")
+	}
+	if _, err := equations.DimensionOf(equations.Exp(length), nil); err == nil {
+		t.Fatal("expected exp of metres to fail")
+	}
+}
+
+func TestCheckDimensions(t *testing.T) {
+	distance, _ := equations.Quantity(10, "m")
+	eq := equations.NewEquation(equations.Var(2, "x", 1), distance)
+
+	if err := equations.CheckDimensions(&eq, map[string]equations.Dimension{"x": equations.Length}); err != nil {
+		t.Fatal(err)
+	}
+	if err := equations.CheckDimensions(&eq, map[string]equations.Dimension{"x": equations.Time}); err == nil {
+		t.Fatalf("expected the sides of %v to disagree", eq)
+	}
+}
+
+func TestSolveTo_carriesUnits(t *testing.T) {
+	distance, _ := equations.Quantity(10, "m")
+	duration, _ := equations.Quantity(4, "s")
+	eq := equations.NewEquation(equations.Mul(equations.Var(1, "v", 1), duration), distance)
+
+	result, err := equations.SolveTo(&eq, "v")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dimension, _ := equations.DimensionOf(*result, nil)
+	if dimension != (equations.Dimension{1, 0, -1, 0, 0, 0, 0}) {
+		t.Fatalf("expected %v to be a velocity, got %v", result, dimension)
+	}
+	speed, err := equations.Convert(*result, "km/h")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if math.Abs(speed-9) > 1e-12 {
+		t.Fatalf("expected %v to be 9 km/h", speed)
+	}
+}
+
+func TestSolveTo_cancelsUnits(t *testing.T) {
+	force, _ := equations.Quantity(10, "N")
+	mass, _ := equations.Quantity(2, "kg")
+	eq := equations.NewEquation(force, equations.Mul(mass, equations.Var(1, "a", 1)))
+
+	result, err := equations.SolveTo(&eq, "a")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result.String() != "(5.000000[m] * 1.000000[s]^-2)" {
+		t.Fatalf("expected %v to be (5.000000[m] * 1.000000[s]^-2)", result)
+	}
+}
+
+func TestQuantity_divisionBindsToTheNextFactor(t *testing.T) {
+	quantity, err := equations.Quantity(1, "kg/m*s")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	dimension, _ := equations.DimensionOf(quantity, nil)
+	if dimension.String() != "m^-1 kg s" {
+		t.Fatalf("expected %v to be m^-1 kg s", dimension)
+	}
+}
+
+func TestDimensionOf_zeroTerm(t *testing.T) {
+	length, _ := equations.Quantity(3, "m")
+
+	dimension, err := equations.DimensionOf(equations.Add(equations.Num(0), length), nil)
+	if err != nil || dimension != equations.Length {
+		t.Fatalf("expected 0 + length to be a length, got %v, %v", dimension, err)
+	}
+	dimension, err = equations.DimensionOf(equations.Sub(length, equations.Num(0)), nil)
+	if err != nil || dimension != equations.Length {
+		t.Fatalf("expected length - 0 to be a length, got %v, %v", dimension, err)
+	}
+}
+
+func TestQuantity_unitsAreNotVariables(t *testing.T) {
+	length, _ := equations.Quantity(2, "m")
+	expr := equations.Mul(equations.Var(3, "x", 1), length)
+
+	if degree, _ := equations.TotalDegree(expr); degree != 1 {
+		t.Fatalf("expected total degree of %v to be 1, got %v", expr, degree)
+	}
+
+	plan := equations.Eliminate(expr)
+	code, err := plan.Go("f", "x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if code != "func f(x float64) float64 {\n\treturn (3*x * 2)\n}\n" {
+		t.Fatalf("expected %v not to mention units", code)
+	}
+	result, err := plan.Evaluate(map[string]float64{"x": 1})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if result != 6 {
+		t.Fatalf("expected %v to be 6", result)
+	}
+}